@@ -267,6 +267,7 @@ const (
 	flagEdit           = "edit"
 	flagAutoApprove    = "auto-approve"
 	flagBaseline       = "baseline"
+	flagCheckDrift     = "check-drift"
 	flagConfig         = "config"
 	flagContext        = "context"
 	flagDevURL         = "dev-url"
@@ -277,6 +278,10 @@ const (
 	flagExclude        = "exclude"
 	flagInclude        = "include"
 	flagFile           = "file"
+	flagConcurrency    = "concurrency"
+	flagContinueOnErr  = "continue-on-error"
+	flagFix            = "fix"
+	flagFixDryRun      = "fix-dry-run"
 	flagFrom           = "from"
 	flagFromShort      = "f"
 	flagFormat         = "format"
@@ -285,8 +290,18 @@ const (
 	flagLatest         = "latest"
 	flagLockTimeout    = "lock-timeout"
 	flagLog            = "log"
+	flagMarkApplied    = "mark-applied"
 	flagPlan           = "plan"
+	flagReset          = "reset"
+	flagResume         = "resume"
+	flagRetries        = "retries"
+	flagRetryBackoff   = "retry-backoff"
 	flagRevisionSchema = "revisions-schema"
+	flagRevisionsAudit = "revisions-audit"
+	flagRollout        = "rollout"
+	flagRollForward    = "roll-forward"
+	flagStatsURL       = "stats-url"
+	flagStmtTimeout    = "statement-timeout"
 	flagSchema         = "schema"
 	flagSchemaShort    = "s"
 	flagTo             = "to"
@@ -295,6 +310,7 @@ const (
 	flagURL            = "url"
 	flagURLShort       = "u"
 	flagVar            = "var"
+	flagWriteBaseline  = "write-baseline"
 	flagQualifier      = "qualifier"
 )
 
@@ -10,7 +10,10 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"slices"
 	"strconv"
+	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
 	cmdmigrate "ariga.io/atlas/cmd/atlas/internal/migrate"
@@ -52,6 +55,10 @@ func migrateApplyRun(cmd *cobra.Command, args []string, flags migrateApplyFlags,
 	if flags.url == "" {
 		return errors.New(`required flag "url" not set`)
 	}
+	hooks, err := newApplyHooks(cmd, env, flags)
+	if err != nil {
+		return err
+	}
 	client, err := env.openClient(ctx, flags.url)
 	if err != nil {
 		return err
@@ -60,7 +67,7 @@ func migrateApplyRun(cmd *cobra.Command, args []string, flags migrateApplyFlags,
 	// Prevent usage printing after input validation.
 	cmd.SilenceUsage = true
 	// Acquire a lock.
-	unlock, err := client.Driver.Lock(ctx, applyLockValue, flags.lockTimeout)
+	unlock, err := client.Driver.Lock(ctx, applyLock(client.URL), flags.lockTimeout)
 	if err != nil {
 		return fmt.Errorf("acquiring database lock: %w", err)
 	}
@@ -69,8 +76,14 @@ func migrateApplyRun(cmd *cobra.Command, args []string, flags migrateApplyFlags,
 	if err := checkRevisionSchemaClarity(cmd, client, flags.revisionSchema); err != nil {
 		return err
 	}
-	var rrw migrate.RevisionReadWriter
-	if rrw, err = entRevisions(ctx, client, flags.revisionSchema); err != nil {
+	var (
+		rrw     migrate.RevisionReadWriter
+		revOpts []cmdmigrate.Option
+	)
+	if flags.revisionsAudit && !flags.dryRun {
+		revOpts = append(revOpts, cmdmigrate.WithAudit(revisionsAudit(dir, os.Args[1:])))
+	}
+	if rrw, err = entRevisions(ctx, client, flags.revisionSchema, revOpts...); err != nil {
 		return err
 	}
 	mrrw, ok := rrw.(cmdmigrate.RevisionReadWriter)
@@ -120,6 +133,11 @@ func migrateApplyRun(cmd *cobra.Command, args []string, flags migrateApplyFlags,
 	}
 	pending = pending[:count]
 	migrate.LogIntro(report, applied, pending)
+	hc := HookContext{Event: hookBeforeApply, Current: report.Current, Target: report.Target}
+	if err := hooks.run(ctx, client.Driver, hc); err != nil {
+		report.Error = err.Error()
+		return errors.Join(err, mr.Done(cmd, flags))
+	}
 	var (
 		mux = tx{
 			dryRun: flags.dryRun,
@@ -127,26 +145,65 @@ func migrateApplyRun(cmd *cobra.Command, args []string, flags migrateApplyFlags,
 			schema: flags.revisionSchema,
 			c:      client,
 			rrw:    rrw,
+			opts:   revOpts,
 		}
-		drv migrate.Driver
+		retry   = cmdmigrate.RetryClassifier(client.Name)
+		backoff = func(n int) time.Duration { return time.Duration(n) * flags.retryBackoff }
 	)
-	for _, f := range pending {
-		if drv, rrw, err = mux.driverFor(ctx, f); err != nil {
-			break
+	if flags.retries <= 0 || flags.dryRun {
+		retry = nil
+	}
+	applyFile := func(f migrate.File, opts []migrate.ExecutorOption, attempt int) error {
+		drv, rrw, err := mux.driverFor(ctx, f)
+		if err != nil {
+			return err
+		}
+		hc.Event, hc.File, hc.Version = hookBeforeFile, f.Name(), f.Version()
+		if err := mux.mayRollback(hooks.runFile(ctx, drv, hc, attempt)); err != nil {
+			return err
 		}
-		if ex, err = migrate.NewExecutor(drv, dir, rrw, opts...); err != nil {
+		ex, err := migrate.NewExecutor(drv, dir, rrw, opts...)
+		if err != nil {
 			return fmt.Errorf("unexpected executor creation error: %w", err)
 		}
-		if err = mux.mayRollback(ex.Execute(ctx, f)); err != nil {
+		if err := mux.mayRollback(ex.Execute(ctx, f)); err != nil {
+			return err
+		}
+		return mux.mayCommit()
+	}
+	for _, f := range pending {
+		var mode string
+		if mode, err = mux.modeFor(f); err != nil {
 			break
 		}
-		if err = mux.mayCommit(); err != nil {
+		fopts := opts
+		// Statements that are executed outside a transaction are retried one by one.
+		// Statements in a transaction cannot be retried, as the failure might abort
+		// or roll back the transaction. Hence, a file that is executed in its own
+		// transaction is retried as a whole, after the transaction was rolled back,
+		// and files that share a transaction (--tx-mode all) are not retried.
+		if retry != nil && mode == txModeNone {
+			fopts = append(slices.Clip(opts), migrate.WithRetryPolicy(flags.retries+1, backoff, retry))
+		}
+		var attempt int
+		apply := func() error {
+			attempt++
+			return applyFile(f, fopts, attempt)
+		}
+		if retry != nil && mode == txModeFile {
+			err = retryFile(ctx, f, rrw, flags.retries, backoff, retry, report, apply)
+		} else {
+			err = apply()
+		}
+		if err != nil {
 			break
 		}
 	}
 	if err == nil {
 		if err = mux.commit(); err == nil {
 			report.Log(migrate.LogDone{})
+			hc.Event, hc.File, hc.Version = hookAfterApply, "", ""
+			err = hooks.run(ctx, client.Driver, hc)
 		}
 	}
 	if err != nil {
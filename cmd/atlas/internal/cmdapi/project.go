@@ -17,6 +17,7 @@ import (
 	"ariga.io/atlas/cmd/atlas/internal/cloudapi"
 	"ariga.io/atlas/cmd/atlas/internal/cmdext"
 	cmdmigrate "ariga.io/atlas/cmd/atlas/internal/migrate"
+	"ariga.io/atlas/cmd/atlas/internal/migratelint"
 	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/schema"
 
@@ -77,14 +78,76 @@ type (
 
 	// Migration represents the migration directory for the Env.
 	Migration struct {
-		Dir             string   `spec:"dir"`
-		Exclude         []string `spec:"exclude"`
-		Format          string   `spec:"format"`
-		Baseline        string   `spec:"baseline"`
-		ExecOrder       string   `spec:"exec_order"`
-		LockTimeout     string   `spec:"lock_timeout"`
-		RevisionsSchema string   `spec:"revisions_schema"`
-		Repo            *Repo    `spec:"repo"`
+		Dir              string   `spec:"dir"`
+		Exclude          []string `spec:"exclude"`
+		Format           string   `spec:"format"`
+		Baseline         string   `spec:"baseline"`
+		ExecOrder        string   `spec:"exec_order"`
+		LockTimeout      string   `spec:"lock_timeout"`
+		StatementTimeout string   `spec:"statement_timeout"`
+		Retries          int      `spec:"retries"`
+		RetryBackoff     string   `spec:"retry_backoff"`
+		Concurrency      int      `spec:"concurrency"`
+		RevisionsSchema  string   `spec:"revisions_schema"`
+		Repo             *Repo    `spec:"repo"`
+		// Hooks executed by 'migrate apply'.
+		Hooks []*MigrationHook `spec:"hook"`
+		// Rollout strategy of 'migrate apply' for multi-instance environments.
+		Rollout *Rollout `spec:"rollout"`
+	}
+
+	// MigrationHook represents a shell command or SQL statements that are
+	// executed at a specific point of 'migrate apply'. SQL statements of
+	// before_file hooks run in the transaction of the file, and are executed
+	// again in case the file is retried, while shell commands run once per
+	// file. For example:
+	//
+	//	migration {
+	//	  hook "before_file" {
+	//	    exec = "./notify.sh"
+	//	  }
+	//	  hook "after_apply" {
+	//	    sql = file("post.sql")
+	//	  }
+	//	}
+	MigrationHook struct {
+		// Event that triggers the hook: before_apply, before_file or after_apply.
+		Event string `spec:"event,name"`
+		// Exec is a shell command that receives the HookContext as JSON on its stdin.
+		Exec string `spec:"exec"`
+		// SQL statements that are executed on the connected database.
+		SQL string `spec:"sql"`
+	}
+
+	// Rollout describes a staged rollout of migrations to the instances
+	// of an environment that was defined using the for_each meta-argument.
+	// For example:
+	//
+	//	migration {
+	//	  rollout {
+	//	    stage {
+	//	      count = 1
+	//	    }
+	//	    stage {
+	//	      percent = 10
+	//	      pause   = "5m"
+	//	    }
+	//	    stage {
+	//	      percent = 100
+	//	    }
+	//	  }
+	//	}
+	Rollout struct {
+		Stages []*RolloutStage `spec:"stage"`
+	}
+
+	// RolloutStage represents a single stage of a rollout. The size of a stage is
+	// cumulative, and is defined either by an absolute count or a percentage of the
+	// environment instances.
+	RolloutStage struct {
+		Count   int    `spec:"count"`   // Number of instances migrated by the end of the stage.
+		Percent int    `spec:"percent"` // Percentage of instances migrated by the end of the stage.
+		Pause   string `spec:"pause"`   // Optional duration to wait before starting the next stage.
 	}
 
 	// Schema represents a schema in the registry.
@@ -356,6 +419,18 @@ func (l *Lint) Extend(global *Lint) *Lint {
 	}
 	if len(l.Extra.Children) == 0 && len(l.Extra.Attrs) == 0 {
 		l.Extra = global.Extra
+	} else if g, ok := global.Extra.Resource("rules"); ok {
+		// Severity rules are merged, and env rules take precedence.
+		r, ok := l.Extra.Resource("rules")
+		if !ok {
+			r = &schemahcl.Resource{Type: "rules"}
+			l.Extra.Children = append(l.Extra.Children, r)
+		}
+		for _, a := range g.Attrs {
+			if _, ok := r.Attr(a.K); !ok {
+				r.SetAttr(a)
+			}
+		}
 	}
 	switch {
 	// Changes detector was configured on the env.
@@ -375,6 +450,64 @@ func (l *Lint) Extend(global *Lint) *Lint {
 	return l
 }
 
+// Severities returns the severity levels configured in the 'rules' block, keyed
+// by diagnostic codes or analyzer names. For example:
+//
+//	lint {
+//	  rules {
+//	    DS103       = "warning"
+//	    BC102       = "error"
+//	    destructive = "off"
+//	  }
+//	}
+func (l *Lint) Severities() (map[string]string, error) {
+	r, ok := l.Remain().Resource("rules")
+	if !ok {
+		return nil, nil
+	}
+	m := make(map[string]string, len(r.Attrs))
+	for _, a := range r.Attrs {
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("parsing severity of lint rule %q: %w", a.K, err)
+		}
+		switch s = strings.ToLower(s); s {
+		case migratelint.SeverityError, migratelint.SeverityWarning, migratelint.SeverityOff:
+			m[a.K] = s
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %q, expect %q, %q or %q", s, a.K, migratelint.SeverityError, migratelint.SeverityWarning, migratelint.SeverityOff)
+		}
+	}
+	return m, nil
+}
+
+// LargeTable returns the thresholds configured in the 'large_table' block, above
+// which tables are considered large by the analyzers. For example:
+//
+//	lint {
+//	  large_table {
+//	    rows  = 1e6
+//	    bytes = 10e9
+//	  }
+//	}
+func (l *Lint) LargeTable() (rows, bytes int64, err error) {
+	r, ok := l.Remain().Resource("large_table")
+	if !ok {
+		return 0, 0, nil
+	}
+	if a, ok := r.Attr("rows"); ok {
+		if rows, err = a.Int64(); err != nil {
+			return 0, 0, fmt.Errorf("parsing large_table rows: %w", err)
+		}
+	}
+	if a, ok := r.Attr("bytes"); ok {
+		if bytes, err = a.Int64(); err != nil {
+			return 0, 0, fmt.Errorf("parsing large_table bytes: %w", err)
+		}
+	}
+	return rows, bytes, nil
+}
+
 // support backward compatibility with the 'log' attribute.
 func (l *Lint) remainedLog() error {
 	at, ok := l.Remain().Attr("log")
@@ -81,6 +81,9 @@ env "local" {
     dir = "file://migrations"
     format = atlas
     lock_timeout = "1s"
+    statement_timeout = "10s"
+    retries = 3
+    retry_backoff = "500ms"
     revisions_schema = "revisions"
     exec_order = LINEAR_SKIP
   }
@@ -155,11 +158,14 @@ env "multi" {
 			DevURL:  "docker://mysql/8",
 			Schemas: []string{"hello", "world"},
 			Migration: &Migration{
-				Dir:             "file://migrations",
-				Format:          cmdmigrate.FormatAtlas,
-				LockTimeout:     "1s",
-				RevisionsSchema: "revisions",
-				ExecOrder:       "LINEAR_SKIP",
+				Dir:              "file://migrations",
+				Format:           cmdmigrate.FormatAtlas,
+				LockTimeout:      "1s",
+				StatementTimeout: "10s",
+				Retries:          3,
+				RetryBackoff:     "500ms",
+				RevisionsSchema:  "revisions",
+				ExecOrder:        "LINEAR_SKIP",
 			},
 			Diff: &Diff{
 				SkipChanges: &SkipChanges{
@@ -498,3 +504,40 @@ func TestDiff_Options(t *testing.T) {
 	require.True(t, opts.Skipped(&schema.DropSchema{}))
 	require.True(t, opts.Skipped(&schema.DropTable{}))
 }
+
+func TestLint_Severities(t *testing.T) {
+	global := &Lint{}
+	global.Extra.Children = []*schemahcl.Resource{
+		{
+			Type: "rules",
+			Attrs: []*schemahcl.Attr{
+				schemahcl.StringAttr("DS103", "warning"),
+				schemahcl.StringAttr("MF101", "off"),
+			},
+		},
+	}
+	env := &Lint{}
+	env.Extra.Children = []*schemahcl.Resource{
+		{Type: "naming"},
+		{
+			Type: "rules",
+			Attrs: []*schemahcl.Attr{
+				schemahcl.StringAttr("DS103", "ERROR"),
+			},
+		},
+	}
+	sev, err := env.Extend(global).Severities()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"DS103": "error", "MF101": "off"}, sev)
+
+	// Envs without rules inherit the global ones.
+	env = &Lint{}
+	env.Extra.Children = []*schemahcl.Resource{{Type: "naming"}}
+	sev, err = env.Extend(global).Severities()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"DS103": "warning", "MF101": "off"}, sev)
+
+	sev, err = (&Lint{}).Severities()
+	require.NoError(t, err)
+	require.Nil(t, sev)
+}
@@ -959,6 +959,207 @@ func TestSchema_ApplyReview(t *testing.T) {
 	})
 }
 
+func TestSchema_ApplyBranded(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(cfg, []byte(`env "test" {
+  lint {
+    branded {
+      naming = true
+      error  = true
+    }
+  }
+}`), 0600))
+	p := filepath.Join(t.TempDir(), "schema.hcl")
+	require.NoError(t, os.WriteFile(p, []byte(`
+schema "main" {}
+table "tasks" {
+  schema = schema.main
+  column "owner" {
+    type    = varchar(14)
+    comment = "branded_id:XYZ"
+  }
+}
+`), 0600))
+	db := openSQLite(t, "")
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaApplyCmd())
+	s, err := runCmd(
+		cmd,
+		"apply",
+		"-c", "file://"+cfg,
+		"--url", db,
+		"--env", "test",
+		"--to", "file://"+p,
+		"--auto-approve",
+	)
+	require.EqualError(t, err, "branded ID issues detected")
+	require.Contains(t, s, "-- branded ID naming violations detected:\n  -- BR103: Branded ID column \"owner\" on table \"tasks\" does not follow the naming convention")
+	require.Contains(t, s, "-- branded ID issues detected:\n  -- BR101: Branded ID column \"owner\" on table \"tasks\": unknown namespace \"XYZ\"")
+
+	// Changes were not applied.
+	s, err = runCmd(schemaInspectCmd(), "-u", db)
+	require.NoError(t, err)
+	require.Equal(t, "schema \"main\" {\n}\n", s)
+}
+
+func TestSchema_Lint(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(cfg, []byte(`
+lint {
+  naming {
+    match = "^[a-z_]+$"
+  }
+  rules {
+    ST101 = "off"
+  }
+}
+env "test" {}
+`), 0600))
+	p := filepath.Join(t.TempDir(), "schema.hcl")
+	require.NoError(t, os.WriteFile(p, []byte(`schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  column "active" {
+    type = bool
+    null = true
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+table "Posts" {
+  schema = schema.main
+  column "author_id" {
+    type = int
+  }
+  foreign_key "author" {
+    columns     = [column.author_id]
+    ref_columns = [table.users.column.id]
+  }
+}
+`), 0600))
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	s, err := runCmd(
+		cmd, "lint",
+		"-c", "file://"+cfg,
+		"--env", "test",
+		"--url", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.NoError(t, err)
+	require.Equal(t, `-- naming violations detected:
+  -- `+p+`:15:1: Table named "Posts" violates the naming policy [NM101]
+-- structural issues detected:
+  -- `+p+`:2:1: Boolean column "active" on table "users" is nullable [ST104]
+  -- `+p+`:15:1: Table "Posts" was created without a primary key [ST102]
+`, s)
+
+	// Upgrade the severity of nullable booleans, and print the report as JSON.
+	cfg = filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(cfg, []byte(`
+lint {
+  rules {
+    ST101 = "off"
+    ST102 = "off"
+    ST104 = "error"
+  }
+}
+env "test" {}
+`), 0600))
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	s, err = runCmd(
+		cmd, "lint",
+		"-c", "file://"+cfg,
+		"--env", "test",
+		"--url", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--format", "{{ json . }}",
+	)
+	require.EqualError(t, err, "structural issues detected")
+	var report cmdlog.SchemaLint
+	require.NoError(t, json.NewDecoder(strings.NewReader(s)).Decode(&report))
+	require.Len(t, report.Steps, 1)
+	require.True(t, report.Steps[0].Error)
+	require.Len(t, report.Steps[0].Diagnostics, 1)
+	require.Equal(t, "ST104", report.Steps[0].Diagnostics[0].Code)
+	require.Equal(t, p, report.Steps[0].Diagnostics[0].Pos.Filename)
+
+	// Schemas without issues.
+	cfg = filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(cfg, []byte(`env "test" {}`), 0600))
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	s, err = runCmd(
+		cmd, "lint",
+		"-c", "file://"+cfg,
+		"--env", "test",
+		"--url", openSQLite(t, "create table t(id int primary key);"),
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "No issues were found in the schema\n", s)
+
+	// A dev database is not required for linting a database.
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	s, err = runCmd(
+		cmd, "lint",
+		"-c", "file://"+cfg,
+		"--env", "test",
+		"--url", openSQLite(t, "create table t(id int);"),
+	)
+	require.NoError(t, err)
+	require.Contains(t, s, `Table "t" was created without a primary key [ST102]`)
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	_, err = runCmd(
+		cmd, "lint",
+		"-c", "file://"+cfg,
+		"--env", "test",
+		"--url", "file://"+p,
+	)
+	require.EqualError(t, err, `required flag "dev-url" not set`)
+}
+
+func TestSchema_LintBranded(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(cfg, []byte(`
+lint {
+  branded {
+    error = true
+  }
+}
+env "test" {}
+`), 0600))
+	p := filepath.Join(t.TempDir(), "schema.hcl")
+	require.NoError(t, os.WriteFile(p, []byte(`schema "main" {}
+table "tasks" {
+  schema = schema.main
+  column "owner" {
+    type    = varchar(14)
+    comment = "branded_id:XYZ"
+  }
+}
+`), 0600))
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	s, err := runCmd(
+		cmd, "lint",
+		"-c", "file://"+cfg,
+		"--env", "test",
+		"--url", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.EqualError(t, err, "branded ID issues detected")
+	require.Contains(t, s, `Branded ID column "owner" on table "tasks": unknown namespace "XYZ"`)
+	require.Contains(t, s, "[BR101]")
+}
+
 func TestSchema_InspectLog(t *testing.T) {
 	db := openSQLite(t, "create table t1 (id integer primary key);create table t2 (name text);")
 	cmd := schemaCmd()
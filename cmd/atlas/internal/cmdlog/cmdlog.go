@@ -85,6 +85,14 @@ type (
 		Error *string `json:"Error,omitempty"` // Assertion error, if any.
 	}
 
+	// StmtRetry represents a retry of a statement that failed with a transient error.
+	StmtRetry struct {
+		Stmt    string        `json:"Stmt,omitempty"`    // SQL statement that failed.
+		Attempt int           `json:"Attempt,omitempty"` // Number of the failed attempt.
+		Delay   time.Duration `json:"Delay,omitempty"`   // Delay before the next attempt.
+		Text    string        `json:"Text,omitempty"`    // Error message as returned by the database.
+	}
+
 	// StmtError groups a statement with its execution error.
 	StmtError struct {
 		Stmt string `json:"Stmt,omitempty"` // SQL statement that failed.
@@ -144,6 +152,9 @@ var (
 {{- if gt .Total 0 }}{{ printf " (%s statements left)" (yellow "%d" .Left) }}{{ end }}
   {{ yellow "--" }} Executed Files:  {{ len .Applied }}{{ if gt .Total 0 }} (last one partially){{ end }}
   {{ yellow "--" }} Pending Files:   {{ add (len .Pending) (len .OutOfOrder) }}{{ if .OutOfOrder }} ({{ if .Pending }}{{ len .OutOfOrder }} {{ end }}out of order){{ end }}
+{{- with .Drift }}
+  {{ yellow "--" }} Schema Drift:    {{ if .Changes }}{{ red "DETECTED" }} ({{ len .Changes }} changes){{ else }}{{ green "NONE" }}{{ end }}
+{{- end }}
 {{- if gt .Total 0 }}
 
 Last migration attempt had errors:
@@ -153,6 +164,17 @@ Last migration attempt had errors:
 
   {{ red "ERROR:" }} {{ .Error }}
 {{- end }}
+{{- if and .Drift .Drift.Changes }}
+
+The database schema drifted from version {{ cyan .Drift.Version }} of the migration directory:
+{{- range .Drift.Summary }}
+  {{ yellow "--" }} {{ . }}
+{{- end }}
+
+Statements to reconcile the database with the migration directory:
+
+{{ .Drift.SQL }}
+{{- end }}
 `)
 			if err != nil {
 				return "", err
@@ -180,6 +202,89 @@ type MigrateStatus struct {
 	Status     string              `json:"Status,omitempty"`     // Status of migration (OK, PENDING)
 	Error      string              `json:"Error,omitempty"`      // Last Error that occurred
 	SQL        string              `json:"SQL,omitempty"`        // SQL that caused the last Error
+	Drift      *SchemaDrift        `json:"Drift,omitempty"`      // Schema drift, if checked
+}
+
+// SchemaDrift describes the difference between the schema of the connected database,
+// and the schema produced by replaying its applied migrations on the dev database.
+type SchemaDrift struct {
+	Version string          // Version the migration directory was replayed to.
+	Changes []schema.Change // Changes that reconcile the database with the migration directory.
+	SQL     string          // SQL statements of the changes.
+}
+
+// Summary returns a textual summary of the drift changes.
+func (d *SchemaDrift) Summary() []string {
+	summary := make([]string, len(d.Changes))
+	for i, c := range d.Changes {
+		summary[i] = changeSummary(c)
+	}
+	return summary
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *SchemaDrift) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version string   `json:"Version,omitempty"`
+		Changes []string `json:"Changes,omitempty"`
+		SQL     string   `json:"SQL,omitempty"`
+	}{d.Version, d.Summary(), d.SQL})
+}
+
+// changeSummary returns a short description of the given change.
+func changeSummary(c schema.Change) string {
+	switch c := c.(type) {
+	case *schema.AddSchema:
+		return fmt.Sprintf("add schema %q", c.S.Name)
+	case *schema.DropSchema:
+		return fmt.Sprintf("drop schema %q", c.S.Name)
+	case *schema.ModifySchema:
+		return fmt.Sprintf("modify schema %q", c.S.Name)
+	case *schema.AddTable:
+		return fmt.Sprintf("add table %q", c.T.Name)
+	case *schema.DropTable:
+		return fmt.Sprintf("drop table %q", c.T.Name)
+	case *schema.RenameTable:
+		return fmt.Sprintf("rename table %q to %q", c.From.Name, c.To.Name)
+	case *schema.ModifyTable:
+		parts := make([]string, len(c.Changes))
+		for i, c := range c.Changes {
+			parts[i] = changeSummary(c)
+		}
+		return fmt.Sprintf("modify table %q: %s", c.T.Name, strings.Join(parts, ", "))
+	case *schema.AddColumn:
+		return fmt.Sprintf("add column %q", c.C.Name)
+	case *schema.DropColumn:
+		return fmt.Sprintf("drop column %q", c.C.Name)
+	case *schema.ModifyColumn:
+		return fmt.Sprintf("modify column %q", c.To.Name)
+	case *schema.RenameColumn:
+		return fmt.Sprintf("rename column %q to %q", c.From.Name, c.To.Name)
+	case *schema.AddIndex:
+		return fmt.Sprintf("add index %q", c.I.Name)
+	case *schema.DropIndex:
+		return fmt.Sprintf("drop index %q", c.I.Name)
+	case *schema.ModifyIndex:
+		return fmt.Sprintf("modify index %q", c.To.Name)
+	case *schema.AddForeignKey:
+		return fmt.Sprintf("add foreign key %q", c.F.Symbol)
+	case *schema.DropForeignKey:
+		return fmt.Sprintf("drop foreign key %q", c.F.Symbol)
+	case *schema.ModifyForeignKey:
+		return fmt.Sprintf("modify foreign key %q", c.To.Symbol)
+	case *schema.AddCheck:
+		return fmt.Sprintf("add check %q", c.C.Name)
+	case *schema.DropCheck:
+		return fmt.Sprintf("drop check %q", c.C.Name)
+	case *schema.AddView:
+		return fmt.Sprintf("add view %q", c.V.Name)
+	case *schema.DropView:
+		return fmt.Sprintf("drop view %q", c.V.Name)
+	case *schema.ModifyView:
+		return fmt.Sprintf("modify view %q", c.To.Name)
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", c), "*schema.")
+	}
 }
 
 // Left returns the amount of statements left to apply (if any).
@@ -205,6 +310,10 @@ type StatusReporter struct {
 	Dir migrate.Dir
 	// Schema name the revision table resides in.
 	Schema string
+	// DevClient, if set, is used to check if the schema of the connected database drifted
+	// from its applied migrations. The check is skipped if the last migration was partially
+	// applied, or if the migration history is non-linear.
+	DevClient *sqlclient.Client
 }
 
 // Report creates and writes a MigrateStatus.
@@ -292,9 +401,85 @@ func (r *StatusReporter) Report(ctx context.Context) (*MigrateStatus, error) {
 			rep.Total = len(stmts)
 		}
 	}
+	if r.DevClient != nil && rep.Total == 0 {
+		if rep.Drift, err = r.drift(ctx, rep); err != nil {
+			return nil, err
+		}
+	}
 	return rep, nil
 }
 
+// drift replays the applied migrations on the dev database, and compares
+// the result with the schema of the connected database.
+func (r *StatusReporter) drift(ctx context.Context, rep *MigrateStatus) (*SchemaDrift, error) {
+	var (
+		d        = &SchemaDrift{}
+		bound    = r.Client.URL.Schema != ""
+		state    = migrate.RealmConn(r.Client, nil)
+		devState = migrate.RealmConn(r.DevClient, nil)
+	)
+	switch {
+	case bound != (r.DevClient.URL.Schema != ""):
+		return nil, errors.New("checking drift: the database and the dev database connections must both be bound to a schema or not. See: https://atlasgo.io/url")
+	case bound:
+		state, devState = migrate.SchemaConn(r.Client, "", nil), migrate.SchemaConn(r.DevClient, "", nil)
+	}
+	var (
+		err      error
+		expected *schema.Realm
+	)
+	if len(rep.Applied) == 0 {
+		expected, err = devState.ReadState(ctx)
+	} else {
+		d.Version = rep.Current
+		ex, err1 := migrate.NewExecutor(r.DevClient.Driver, r.Dir, migrate.NopRevisionReadWriter{})
+		if err1 != nil {
+			return nil, err1
+		}
+		expected, err = ex.Replay(ctx, devState, migrate.ReplayToVersion(d.Version))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("checking drift: %w", err)
+	}
+	actual, err := state.ReadState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checking drift: %w", err)
+	}
+	r.excludeRevisions(actual)
+	if bound {
+		// Schema names are irrelevant when comparing schema contents.
+		actual.Schemas[0].Name, expected.Schemas[0].Name = "", ""
+		d.Changes, err = r.Client.SchemaDiff(actual.Schemas[0], expected.Schemas[0])
+	} else {
+		d.Changes, err = r.Client.RealmDiff(actual, expected)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("checking drift: %w", err)
+	}
+	if len(d.Changes) > 0 {
+		if d.SQL, err = fmtPlan(ctx, r.Client, d.Changes, nil); err != nil {
+			return nil, fmt.Errorf("checking drift: %w", err)
+		}
+		d.SQL = strings.TrimSuffix(d.SQL, "\n")
+	}
+	return d, nil
+}
+
+// excludeRevisions removes the revisions table from the inspected state of the connected
+// database, and the schema it resides in, in case it holds no other resources.
+func (r *StatusReporter) excludeRevisions(state *schema.Realm) {
+	for i, s := range state.Schemas {
+		if s.Name != r.Schema && (len(state.Schemas) > 1 || r.Client.URL.Schema != r.Schema) {
+			continue
+		}
+		s.Tables = slices.DeleteFunc(s.Tables, func(t *schema.Table) bool { return t.Name == revision.Table })
+		if s.Name != r.Client.URL.Schema && len(s.Tables) == 0 && len(s.Views) == 0 && len(s.Funcs) == 0 && len(s.Procs) == 0 && len(s.Objects) == 0 {
+			state.Schemas = slices.Delete(state.Schemas, i, i+1)
+		}
+		return
+	}
+}
+
 const (
 	statusOK      = "OK"
 	statusPending = "PENDING"
@@ -385,6 +570,102 @@ func (r *RevisionOp) ColoredVersion() string {
 	return c + " " + r.Version
 }
 
+var (
+	// RepairTemplateFuncs are global functions available in repair report templates.
+	RepairTemplateFuncs = WithColorFuncs(template.FuncMap{
+		"json": jsonEncode,
+	})
+	// MigrateRepairTemplate holds the default template of the 'migrate repair' command.
+	MigrateRepairTemplate = template.Must(template.New("repair").
+		Funcs(RepairTemplateFuncs).Parse(`
+{{- with .Revision -}}
+Migration {{ cyan .Version }}{{ with .Description }} ({{ . }}){{ end }}: {{ .Applied }} of {{ .Total }} statements applied
+{{- end }}
+{{- with .Action }}
+  {{ yellow "--" }} {{ . }}
+{{- end }}
+{{- with .Revision.ErrorStmt }}
+  {{ yellow "--" }} Failed Statement: {{ . }}
+  {{ yellow "--" }} {{ red "ERROR:" }} {{ $.Revision.Error }}
+{{- end }}
+{{- with .Pending }}
+
+Remaining statements:
+{{- range . }}
+  {{ yellow "%d." .Pos }} {{ .Text }}
+{{- end }}
+{{- end }}
+`))
+)
+
+type (
+	// MigrateRepair contains a summary of the 'migrate repair' command.
+	MigrateRepair struct {
+		Revision *migrate.Revision `json:"Revision"`          // Revision after the repair.
+		Action   string            `json:"Action,omitempty"`  // Action taken, if any.
+		Pending  []*RepairStmt     `json:"Pending,omitempty"` // Statements that were not applied.
+	}
+	// RepairStmt is a statement of a repaired migration file.
+	RepairStmt struct {
+		Pos  int    `json:"Pos"`  // Position of the statement in the file, starting at 1.
+		Text string `json:"Text"` // Text of the statement.
+	}
+)
+
+// NewMigrateRepair returns a MigrateRepair for the given revision and the statements of its file.
+func NewMigrateRepair(rev *migrate.Revision, stmts []*migrate.Stmt) *MigrateRepair {
+	r := &MigrateRepair{Revision: rev}
+	for i := rev.Applied; i < len(stmts) && rev.Applied < rev.Total; i++ {
+		r.Pending = append(r.Pending, &RepairStmt{Pos: i + 1, Text: stmts[i].Text})
+	}
+	return r
+}
+
+// MigrateSquashTemplate holds the default template of the 'migrate squash' command.
+var MigrateSquashTemplate = template.Must(template.New("squash").
+	Funcs(ColorTemplateFuncs).Parse(`
+{{- $n := len .Squashed -}}
+Squashed {{ $n }} migration file{{ if ne $n 1 }}s{{ end }} into {{ cyan .File }} ({{ .Stmts }} statement{{ if ne .Stmts 1 }}s{{ end }}):
+{{- range .Squashed }}
+  {{ yellow "--" }} {{ . }}
+{{- end }}
+
+Databases that applied some of the squashed files should be set to version {{ cyan .Version }}, so it is not re-run:
+{{- range .Mapping }}
+  {{ .Version }}: {{ with .Missing }}apply the changes of {{ range $i, $f := . }}{{ if $i }}, {{ end }}{{ $f }}{{ end }} manually, then {{ end }}run 'atlas migrate set {{ $.Version }}'
+{{- end }}
+`))
+
+type (
+	// MigrateSquash contains a summary of the 'migrate squash' command.
+	MigrateSquash struct {
+		File     string           `json:"File"`     // Name of the squashed file.
+		Version  string           `json:"Version"`  // Version of the squashed file.
+		Stmts    int              `json:"Stmts"`    // Number of statements in the squashed file.
+		Squashed []string         `json:"Squashed"` // Names of the files that were squashed.
+		Mapping  []*SquashMapping `json:"Mapping"`  // Mapping of the squashed versions.
+	}
+	// SquashMapping describes how to map a database that applied a squashed version.
+	SquashMapping struct {
+		Version string   `json:"Version"`           // Version of a squashed file.
+		Missing []string `json:"Missing,omitempty"` // Squashed files following this version.
+	}
+)
+
+// NewMigrateSquash returns a MigrateSquash for the given file and the files it replaced.
+func NewMigrateSquash(f migrate.File, stmts int, squashed []migrate.File) *MigrateSquash {
+	r := &MigrateSquash{File: f.Name(), Version: f.Version(), Stmts: stmts}
+	for i, s := range squashed {
+		r.Squashed = append(r.Squashed, s.Name())
+		m := &SquashMapping{Version: s.Version()}
+		for _, s := range squashed[i+1:] {
+			m.Missing = append(m.Missing, s.Name())
+		}
+		r.Mapping = append(r.Mapping, m)
+	}
+	return r
+}
+
 var (
 	// ApplyTemplateFuncs are global functions available in apply report templates.
 	ApplyTemplateFuncs = WithColorFuncs(template.FuncMap{
@@ -429,6 +710,9 @@ var (
 	{{- range $f.Applied }}
 		{{- println "   " (cyan "->") (indent_ln . 7) }}
 	{{- end }}
+	{{- range $f.Retries }}
+		{{- printf "    %s attempt %d failed: %s (retried after %s)\n" (yellow "--") .Attempt .Text .Delay }}
+	{{- end }}
 	{{- with .Error }}
 		{{- println "   " (redBgWhiteFg .Text) }}
 	{{- else }}
@@ -471,6 +755,7 @@ type (
 		Skipped int           // Amount of skipped SQL statements in a partially applied file.
 		Applied []string      // SQL statements applied with success
 		Checks  []*FileChecks // Assertion checks
+		Retries []*StmtRetry  // Retries of transient failures
 		Error   *StmtError
 	}
 )
@@ -499,6 +784,11 @@ func (a *MigrateApply) Log(e migrate.LogEntry) {
 	case migrate.LogFile:
 		if l := len(a.Applied); l > 0 {
 			f := a.Applied[l-1]
+			// A file that is retried as a whole.
+			if f.Version() == e.File.Version() {
+				f.Skipped = e.Skip
+				return
+			}
 			f.End = time.Now()
 		}
 		a.Applied = append(a.Applied, &AppliedFile{
@@ -540,6 +830,19 @@ func (a *MigrateApply) Log(e migrate.LogEntry) {
 	case migrate.LogStmt:
 		f := a.Applied[len(a.Applied)-1]
 		f.Applied = append(f.Applied, a.MaskedText(e.Stmt))
+	case migrate.LogRetry:
+		f := a.Applied[len(a.Applied)-1]
+		f.Retries = append(f.Retries, &StmtRetry{
+			Stmt:    a.MaskedText(e.Stmt),
+			Attempt: e.Attempt,
+			Delay:   e.Delay,
+			Text:    a.MaskedErrorText(migrate.LogError{Error: e.Error, Stmt: e.Stmt}),
+		})
+		// The file failed in a transaction, and is retried as a whole.
+		// Its statements were rolled back, and are executed again.
+		if e.File != nil {
+			f.Applied, f.Checks, f.Error, f.End, a.End = nil, nil, nil, time.Time{}, time.Time{}
+		}
 	case migrate.LogError:
 		// Error during migration.
 		if l := len(a.Applied); l > 0 {
@@ -1000,6 +1303,57 @@ func sqlDiff(diff *SchemaDiff, indent ...string) (string, error) {
 	return fmtPlan(diff.ctx, diff.client, diff.Changes, indent)
 }
 
+type (
+	// SchemaLint contains a summary of the 'schema lint' command.
+	SchemaLint struct {
+		Steps []*SchemaLintStep `json:"Steps,omitempty"` // Reports of the analysis.
+	}
+
+	// SchemaLintStep holds a report of the analysis, with the diagnostics of the report.
+	SchemaLintStep struct {
+		Text        string                  `json:"Text"`                  // Report text.
+		Desc        string                  `json:"Desc,omitempty"`        // Optional description (secondary text).
+		Error       bool                    `json:"Error,omitempty"`       // Report is an error report.
+		Diagnostics []*SchemaLintDiagnostic `json:"Diagnostics,omitempty"` // Report diagnostics.
+	}
+
+	// SchemaLintDiagnostic is a text associated with the position of a schema element.
+	SchemaLintDiagnostic struct {
+		Pos  *schema.Pos `json:"Pos,omitempty"`  // Element position, if loaded from a file.
+		Text string      `json:"Text"`           // Diagnostic text.
+		Code string      `json:"Code,omitempty"` // Code describes the check.
+	}
+)
+
+var (
+	// SchemaLintFuncs are global functions available in lint report templates.
+	SchemaLintFuncs = WithColorFuncs(template.FuncMap{
+		"json": jsonEncode,
+	})
+	// SchemaLintTemplate holds the default template of the 'schema lint' command.
+	SchemaLintTemplate = template.Must(template.
+		New("schema_lint").
+		Funcs(SchemaLintFuncs).
+		Parse(`{{- range .Steps -}}
+-- {{ .Text }}:
+{{ range .Diagnostics -}}
+{{ "  -- " }}{{ with .Location }}{{ . }}: {{ end }}{{ .Text }}{{ with .Code }} [{{ . }}]{{ end }}
+{{ end -}}
+{{- else -}}
+No issues were found in the schema
+{{ end -}}
+`))
+)
+
+// Location returns the location of the diagnostic in the
+// "file:line:column" format, or an empty string if unknown.
+func (d *SchemaLintDiagnostic) Location() string {
+	if d.Pos == nil || d.Pos.Filename == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%d", d.Pos.Filename, d.Pos.Start.Line, d.Pos.Start.Column)
+}
+
 func fmtPlan(ctx context.Context, client *sqlclient.Client, changes schema.Changes, indent []string, edit ...func(*migrate.Plan)) (string, error) {
 	if len(indent) > 1 {
 		return "", fmt.Errorf("unexpected number of arguments: %d", len(indent))
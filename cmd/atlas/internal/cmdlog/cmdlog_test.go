@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"path/filepath"
 	"strings"
@@ -647,6 +648,87 @@ func TestMigrateApply(t *testing.T) {
 `, b.String())
 }
 
+func TestMigrateApply_Retries(t *testing.T) {
+	var (
+		b   bytes.Buffer
+		d   migrate.MemDir
+		log = &cmdlog.MigrateApply{Start: time.Now()}
+	)
+	color.NoColor = true
+	require.NoError(t, d.WriteFile("20240116000001.sql", nil))
+	files, err := d.Files()
+	require.NoError(t, err)
+	stmt := &migrate.Stmt{Text: "UPDATE t SET c = 1;"}
+	log.Log(migrate.LogExecution{To: files[0].Version(), Files: files})
+	log.Log(migrate.LogFile{File: files[0]})
+	log.Log(migrate.LogStmt{SQL: stmt.Text, Stmt: stmt})
+	log.Log(migrate.LogRetry{SQL: stmt.Text, Stmt: stmt, Attempt: 1, Delay: time.Second, Error: errors.New("database is locked")})
+	log.Log(migrate.LogRetry{SQL: stmt.Text, Stmt: stmt, Attempt: 2, Delay: 2 * time.Second, Error: errors.New("database is locked")})
+	require.Len(t, log.Applied, 1)
+	require.Len(t, log.Applied[0].Retries, 2)
+	require.Equal(t, stmt.Text, log.Applied[0].Retries[0].Stmt)
+
+	log.End = log.Start.Add(time.Millisecond * 10)
+	log.Applied[0].Start, log.Applied[0].End = log.Start, log.Start.Add(time.Millisecond*5)
+	require.NoError(t, cmdlog.MigrateApplyTemplate.Execute(&b, log))
+	require.Equal(t, `Migrating to version 20240116000001 (1 migrations in total):
+
+  -- migrating version 20240116000001
+    -> UPDATE t SET c = 1;
+    -- attempt 1 failed: database is locked (retried after 1s)
+    -- attempt 2 failed: database is locked (retried after 2s)
+  -- ok (5ms)
+
+  -------------------------
+  -- 10ms
+  -- 1 migration
+  -- 1 sql statement
+`, b.String())
+}
+
+func TestMigrateApply_RetryFile(t *testing.T) {
+	var (
+		b   bytes.Buffer
+		d   migrate.MemDir
+		log = &cmdlog.MigrateApply{Start: time.Now()}
+	)
+	color.NoColor = true
+	require.NoError(t, d.WriteFile("20240116000001.sql", nil))
+	files, err := d.Files()
+	require.NoError(t, err)
+	s1, s2 := &migrate.Stmt{Text: "CREATE TABLE t(c int);"}, &migrate.Stmt{Text: "UPDATE t SET c = 1;"}
+	log.Log(migrate.LogExecution{To: files[0].Version(), Files: files})
+	log.Log(migrate.LogFile{File: files[0]})
+	log.Log(migrate.LogStmt{SQL: s1.Text, Stmt: s1})
+	log.Log(migrate.LogStmt{SQL: s2.Text, Stmt: s2})
+	log.Log(migrate.LogError{SQL: s2.Text, Stmt: s2, Error: errors.New("database is locked")})
+	// The file is executed again after its transaction was rolled back.
+	log.Log(migrate.LogRetry{SQL: s2.Text, Stmt: s2, File: files[0], Attempt: 1, Delay: time.Second, Error: errors.New("database is locked")})
+	log.Log(migrate.LogFile{File: files[0]})
+	log.Log(migrate.LogStmt{SQL: s1.Text, Stmt: s1})
+	log.Log(migrate.LogStmt{SQL: s2.Text, Stmt: s2})
+	require.Len(t, log.Applied, 1)
+	require.Nil(t, log.Applied[0].Error)
+	require.Equal(t, []string{s1.Text, s2.Text}, log.Applied[0].Applied)
+
+	log.End = log.Start.Add(time.Millisecond * 10)
+	log.Applied[0].Start, log.Applied[0].End = log.Start, log.Start.Add(time.Millisecond*5)
+	require.NoError(t, cmdlog.MigrateApplyTemplate.Execute(&b, log))
+	require.Equal(t, `Migrating to version 20240116000001 (1 migrations in total):
+
+  -- migrating version 20240116000001
+    -> CREATE TABLE t(c int);
+    -> UPDATE t SET c = 1;
+    -- attempt 1 failed: database is locked (retried after 1s)
+  -- ok (5ms)
+
+  -------------------------
+  -- 10ms
+  -- 1 migration
+  -- 2 sql statements
+`, b.String())
+}
+
 func TestReporter_Status(t *testing.T) {
 	var (
 		buf strings.Builder
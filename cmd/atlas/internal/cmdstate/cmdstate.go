@@ -59,6 +59,18 @@ func (f File[T]) Write(t T) error {
 	return os.WriteFile(path, buf, 0666)
 }
 
+// Remove removes the file from the file system, if it exists.
+func (f File[T]) Remove() error {
+	path, err := f.Path()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 // Path returns the path to the file.
 func (f File[T]) Path() (string, error) {
 	name := f.Name
@@ -28,6 +28,11 @@ func TestFile(t *testing.T) {
 	v, err = f.Read()
 	require.NoError(t, err)
 	require.Equal(t, T{V: "v"}, v)
+	require.NoError(t, f.Remove())
+	require.NoError(t, f.Remove(), "removing a missing file is a nop")
+	v, err = f.Read()
+	require.NoError(t, err)
+	require.Equal(t, T{}, v)
 
 	home := t.TempDir()
 	t.Setenv("HOME", home)
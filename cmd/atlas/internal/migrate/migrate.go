@@ -15,12 +15,14 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/migrate/ent"
 	"ariga.io/atlas/cmd/atlas/internal/migrate/ent/revision"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
 	"ariga.io/atlas/sql/sqlite"
@@ -48,10 +50,27 @@ type (
 		ac     *sqlclient.Client // underlying Atlas client
 		ec     *ent.Client       // underlying Ent client
 		schema string            // name of the schema the revision table resides in
+		audit  *Audit            // optional audit information to record
+		// versions whose audit information was recorded by this instance.
+		audited map[string]bool
 	}
 
 	// Option allows to configure EntRevisions by using functional arguments.
 	Option func(*EntRevisions) error
+
+	// Audit holds the operator metadata that is recorded in the audit columns
+	// of the revisions table, alongside the content of the executed file.
+	Audit struct {
+		Dir    migrate.Dir // Migration directory, used to record the executed SQL.
+		User   string      // OS user that executed the migration.
+		Host   string      // Hostname of the executing machine.
+		Commit string      // Git commit of the migration directory.
+		Branch string      // Git branch of the migration directory.
+		Args   string      // Redacted CLI arguments.
+
+		once  sync.Once
+		files map[string]migrate.File
+	}
 )
 
 // Dialect returns the "ent dialect" of the Ent client.
@@ -73,8 +92,23 @@ func EntDialect(d string) string {
 	}
 }
 
+// RetryClassifier returns the classifier of transient statement
+// execution errors of the given driver, or nil if not supported.
+func RetryClassifier(d string) migrate.RetryClassifier {
+	switch {
+	case d == mysql.DriverName, d == mysql.DriverMaria:
+		return mysql.IsRetryable
+	case d == postgres.DriverName:
+		return postgres.IsRetryable
+	case d == sqlite.DriverName, strings.HasPrefix(d, "libsql"):
+		return sqlite.IsRetryable
+	default:
+		return nil
+	}
+}
+
 // RevisionsForClient creates a new RevisionReadWriter for the given sqlclient.Client.
-func RevisionsForClient(ctx context.Context, ac *sqlclient.Client, schema string) (RevisionReadWriter, error) {
+func RevisionsForClient(ctx context.Context, ac *sqlclient.Client, schema string, opts ...Option) (RevisionReadWriter, error) {
 	// If the driver supports the RevisionReadWriter interface, use it.
 	if drv, ok := ac.Driver.(interface {
 		RevisionsReadWriter(context.Context, string) (migrate.RevisionReadWriter, error)
@@ -88,7 +122,7 @@ func RevisionsForClient(ctx context.Context, ac *sqlclient.Client, schema string
 		}
 		return nil, fmt.Errorf("unexpected revision read-writer type: %T", rrw)
 	}
-	return NewEntRevisions(ctx, ac, WithSchema(schema))
+	return NewEntRevisions(ctx, ac, append([]Option{WithSchema(schema)}, opts...)...)
 }
 
 // NewEntRevisions creates a new EntRevisions with the given sqlclient.Client.
@@ -136,6 +170,15 @@ func WithSchema(s string) Option {
 	}
 }
 
+// WithAudit enables recording the given audit information in the revisions table.
+// Note that the audit columns are added to the revisions table by Migrate.
+func WithAudit(a *Audit) Option {
+	return func(r *EntRevisions) error {
+		r.audit = a
+		return nil
+	}
+}
+
 // Ident returns the table identifier.
 func (r *EntRevisions) Ident() *migrate.TableIdent {
 	return &migrate.TableIdent{Name: revision.Table, Schema: r.schema}
@@ -196,11 +239,98 @@ func (r *EntRevisions) WriteRevision(ctx context.Context, rev *migrate.Revision)
 	if rev.Version == revisionID {
 		return errors.New("writing the revision-table identifier is not allowed")
 	}
-	return r.ec.Revision.Create().
+	err := r.ec.Revision.Create().
 		SetRevision(rev).
 		OnConflict(sql.ConflictColumns(revision.FieldID)).
 		UpdateNewValues().
 		Exec(ctx)
+	if err != nil || r.audit == nil {
+		return err
+	}
+	return r.writeAudit(ctx, rev)
+}
+
+// Audit columns of the revisions table.
+const (
+	auditColumnSQL    = "audit_sql"
+	auditColumnUser   = "audit_user"
+	auditColumnHost   = "audit_host"
+	auditColumnCommit = "audit_commit"
+	auditColumnBranch = "audit_branch"
+	auditColumnArgs   = "audit_args"
+)
+
+// auditColumns returns the definitions of the audit columns.
+func (r *EntRevisions) auditColumns() []*schema.Column {
+	columns := make([]*schema.Column, 0, 6)
+	for _, n := range []string{auditColumnSQL, auditColumnUser, auditColumnHost, auditColumnCommit, auditColumnBranch, auditColumnArgs} {
+		t := &schema.StringType{T: "text"}
+		// MySQL TEXT columns are limited to 64KB.
+		if n == auditColumnSQL && r.Dialect() == dialect.MySQL {
+			t.T = "longtext"
+		}
+		columns = append(columns, schema.NewColumn(n).SetType(t).SetNull(true))
+	}
+	return columns
+}
+
+// writeAudit records the audit information of the given revision. The revision of a file
+// is written after each of its statements, but its audit information is recorded only once.
+func (r *EntRevisions) writeAudit(ctx context.Context, rev *migrate.Revision) error {
+	if r.audited[rev.Version] {
+		return nil
+	}
+	b := sql.Dialect(r.Dialect()).Update(revision.Table).
+		Set(auditColumnUser, r.audit.User).
+		Set(auditColumnHost, r.audit.Host).
+		Set(auditColumnCommit, r.audit.Commit).
+		Set(auditColumnBranch, r.audit.Branch).
+		Set(auditColumnArgs, r.audit.Args).
+		Where(sql.EQ(revision.FieldID, rev.Version))
+	if r.Dialect() != dialect.SQLite && r.schema != "" {
+		b.Schema(r.schema)
+	}
+	f, err := r.audit.file(rev.Version)
+	if err != nil {
+		return err
+	}
+	withSQL := f != nil && rev.Type.Has(migrate.RevisionTypeExecute)
+	if withSQL {
+		b.Set(auditColumnSQL, string(f.Bytes()))
+	}
+	query, args := b.Query()
+	if _, err := r.ac.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("writing revision audit: %w", err)
+	}
+	// Revisions that are executed after they were recorded
+	// (e.g., baseline or resolved ones) still lack their SQL.
+	if withSQL || f == nil {
+		if r.audited == nil {
+			r.audited = make(map[string]bool)
+		}
+		r.audited[rev.Version] = true
+	}
+	return nil
+}
+
+// file returns the migration file of the given version, or nil if there is no such file.
+func (a *Audit) file(v string) (migrate.File, error) {
+	if a.Dir == nil {
+		return nil, nil
+	}
+	var err error
+	a.once.Do(func() {
+		var files []migrate.File
+		if files, err = a.Dir.Files(); err != nil {
+			err = fmt.Errorf("reading migration files for audit: %w", err)
+			return
+		}
+		a.files = make(map[string]migrate.File, len(files))
+		for _, f := range files {
+			a.files[f.Version()] = f
+		}
+	})
+	return a.files[v], err
 }
 
 // DeleteRevision deletes a revision from the revisions table.
@@ -272,20 +402,54 @@ func (r *EntRevisions) Migrate(ctx context.Context) (err error) {
 				case *schema.AddTable:
 					r.maySetSchemaQualifier(cs.T)
 					if cs.T.Name == revision.Table {
+						if r.audit != nil {
+							cs.T.AddColumns(r.auditColumns()...)
+						}
 						return schema.Changes{cs}, nil
 					}
 				case *schema.ModifyTable:
 					r.maySetSchemaQualifier(cs.T)
 					if cs.T.Name == revision.Table {
-						return schema.Changes{cs}, nil
+						return r.auditChanges(current, cs), nil
 					}
 				}
 			}
+			if t, ok := current.Table(revision.Table); ok && r.audit != nil {
+				r.maySetSchemaQualifier(t)
+				return r.auditChanges(current, &schema.ModifyTable{T: t}), nil
+			}
 			return nil, nil
 		})
 	}))...)
 }
 
+// auditChanges ensures the audit columns of the revisions table are not dropped, as they are
+// not part of the Ent schema, and adds the missing ones in case auditing is enabled.
+func (r *EntRevisions) auditChanges(current *schema.Schema, m *schema.ModifyTable) schema.Changes {
+	audit := r.auditColumns()
+	m.Changes = slices.DeleteFunc(m.Changes, func(c schema.Change) bool {
+		d, ok := c.(*schema.DropColumn)
+		return ok && slices.ContainsFunc(audit, func(c *schema.Column) bool { return c.Name == d.C.Name })
+	})
+	if t, ok := current.Table(revision.Table); ok {
+		for _, c := range audit {
+			switch c1, ok := t.Column(c.Name); {
+			case ok && m.T != t:
+				// Keep existing columns in case the table is recreated (e.g., SQLite).
+				if _, ok := m.T.Column(c.Name); !ok {
+					m.T.AddColumns(c1)
+				}
+			case !ok && r.audit != nil:
+				m.Changes = append(m.Changes, &schema.AddColumn{C: c})
+			}
+		}
+	}
+	if len(m.Changes) == 0 {
+		return nil
+	}
+	return schema.Changes{m}
+}
+
 // maySetSchemaQualifier sets the schema on the atlas_schema_revisions table
 // for cases the migration should use qualified identifiers due to some limitation
 // in PostgreSQL services that use "connection pooler" and do not support the "search_path"
@@ -6,6 +6,7 @@ package migrate
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/url"
@@ -73,6 +74,58 @@ func TestNewEntRevisions(t *testing.T) {
 	runRevisionsTests(ctx, t, c.Driver, r)
 }
 
+func TestEntRevisions_Audit(t *testing.T) {
+	ctx := context.Background()
+	c, err := sqlclient.Open(ctx, "sqlite://file:"+filepath.Join(t.TempDir(), "audit.db")+"?_fk=1")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, c.Close()) })
+	dir := migrate.OpenMemDir(t.Name())
+	t.Cleanup(func() { require.NoError(t, dir.Close()) })
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t(c int);\n")))
+	audit := &Audit{Dir: dir, User: "a8m", Host: "local", Commit: "abc", Branch: "master", Args: "migrate apply"}
+	r, err := NewEntRevisions(ctx, c, WithAudit(audit))
+	require.NoError(t, err)
+	require.NoError(t, r.Migrate(ctx))
+	require.NoError(t, r.WriteRevision(ctx, &migrate.Revision{Version: "1", Type: migrate.RevisionTypeExecute, ExecutedAt: time.Now()}))
+	require.NoError(t, r.WriteRevision(ctx, &migrate.Revision{Version: "2", Type: migrate.RevisionTypeBaseline, ExecutedAt: time.Now()}))
+
+	var sqlText, user, host, commit, branch, args sql.NullString
+	row := c.DB.QueryRowContext(ctx, "SELECT audit_sql, audit_user, audit_host, audit_commit, audit_branch, audit_args FROM atlas_schema_revisions WHERE version = '1'")
+	require.NoError(t, row.Scan(&sqlText, &user, &host, &commit, &branch, &args))
+	require.Equal(t, "CREATE TABLE t(c int);\n", sqlText.String)
+	require.Equal(t, []string{"a8m", "local", "abc", "master", "migrate apply"}, []string{user.String, host.String, commit.String, branch.String, args.String})
+	row = c.DB.QueryRowContext(ctx, "SELECT audit_sql, audit_user FROM atlas_schema_revisions WHERE version = '2'")
+	require.NoError(t, row.Scan(&sqlText, &user))
+	require.False(t, sqlText.Valid, "no SQL is recorded for baseline revisions")
+	require.Equal(t, "a8m", user.String)
+
+	// The revision of a file is written after each statement, but its audit is recorded once.
+	_, err = c.DB.ExecContext(ctx, "UPDATE atlas_schema_revisions SET audit_sql = 'recorded' WHERE version = '1'")
+	require.NoError(t, err)
+	require.NoError(t, r.WriteRevision(ctx, &migrate.Revision{Version: "1", Type: migrate.RevisionTypeExecute, Applied: 1, ExecutedAt: time.Now()}))
+	row = c.DB.QueryRowContext(ctx, "SELECT audit_sql FROM atlas_schema_revisions WHERE version = '1'")
+	require.NoError(t, row.Scan(&sqlText))
+	require.Equal(t, "recorded", sqlText.String)
+
+	// Audit columns are not dropped when auditing is disabled.
+	r, err = NewEntRevisions(ctx, c)
+	require.NoError(t, err)
+	require.NoError(t, r.Migrate(ctx))
+	require.NoError(t, r.WriteRevision(ctx, &migrate.Revision{Version: "3", Type: migrate.RevisionTypeExecute, ExecutedAt: time.Now()}))
+	revs, err := r.ReadRevisions(ctx)
+	require.NoError(t, err)
+	require.Len(t, revs, 3)
+	s, err := c.InspectSchema(ctx, "", nil)
+	require.NoError(t, err)
+	tr, ok := s.Table(revision.Table)
+	require.True(t, ok)
+	_, ok = tr.Column("audit_sql")
+	require.True(t, ok)
+	row = c.DB.QueryRowContext(ctx, "SELECT audit_sql FROM atlas_schema_revisions WHERE version = '1'")
+	require.NoError(t, row.Scan(&sqlText))
+	require.Equal(t, "recorded", sqlText.String)
+}
+
 func TestDirURL(t *testing.T) {
 	localDir := t.TempDir()
 	tests := []struct {
@@ -0,0 +1,154 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+//go:build !ent
+
+package migratelint
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// A Baseline holds the fingerprints of known diagnostics. Diagnostics that
+	// match an entry in the baseline are suppressed by the Runner, and only new
+	// diagnostics are reported.
+	Baseline struct {
+		Entries []*BaselineEntry `json:"Entries"`
+
+		mu       sync.Mutex
+		matched  map[*BaselineEntry]bool // entries that matched a diagnostic.
+		analyzed map[string]bool         // files that were analyzed.
+	}
+
+	// A BaselineEntry is the fingerprint of a single diagnostic.
+	BaselineEntry struct {
+		File string `json:"File"`           // Name of the file.
+		Code string `json:"Code,omitempty"` // Diagnostic code. For example, DS103.
+		Hash string `json:"Hash"`           // Hash of the statement text.
+	}
+)
+
+// ReadBaseline reads the baseline from the given path.
+// An empty baseline is returned if the file does not exist.
+func ReadBaseline(path string) (*Baseline, error) {
+	b := &Baseline{}
+	switch buf, err := os.ReadFile(path); {
+	case errors.Is(err, fs.ErrNotExist):
+		return b, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading baseline file: %w", err)
+	default:
+		if err := json.Unmarshal(buf, b); err != nil {
+			return nil, fmt.Errorf("parsing baseline file %q: %w", path, err)
+		}
+		return b, nil
+	}
+}
+
+// Write writes the baseline to the given path.
+func (b *Baseline) Write(path string) error {
+	slices.SortStableFunc(b.Entries, func(x, y *BaselineEntry) int {
+		return cmp.Or(cmp.Compare(x.File, y.File), cmp.Compare(x.Code, y.Code), cmp.Compare(x.Hash, y.Hash))
+	})
+	if b.Entries == nil {
+		b.Entries = make([]*BaselineEntry, 0)
+	}
+	buf, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(buf, '\n'), 0644)
+}
+
+// Update replaces the entries of the analyzed files in the
+// report with the fingerprints of their diagnostics.
+func (b *Baseline) Update(r *SummaryReport) {
+	var entries []*BaselineEntry
+	for _, f := range r.Files {
+		if f.File == nil {
+			continue
+		}
+		b.Entries = slices.DeleteFunc(b.Entries, func(e *BaselineEntry) bool { return e.File == f.Name })
+		for _, rp := range f.Reports {
+			for _, d := range rp.Diagnostics {
+				entries = append(entries, fingerprint(f.File, d))
+			}
+		}
+	}
+	b.Entries = append(b.Entries, entries...)
+}
+
+// Prune removes the entries of analyzed files that did not match any
+// diagnostic, and returns them. Note that the baseline file is not
+// rewritten, and it is up to the caller to report the stale entries.
+func (b *Baseline) Prune() []*BaselineEntry {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var stale []*BaselineEntry
+	b.Entries = slices.DeleteFunc(b.Entries, func(e *BaselineEntry) bool {
+		if b.analyzed[e.File] && !b.matched[e] {
+			stale = append(stale, e)
+			return true
+		}
+		return false
+	})
+	return stale
+}
+
+// suppress returns a function that reports if a diagnostic of the given file
+// matches an entry in the baseline. Each entry can match a single diagnostic.
+func (b *Baseline) suppress(f *sqlcheck.File) func(sqlcheck.Diagnostic) bool {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.matched == nil {
+		b.analyzed, b.matched = make(map[string]bool), make(map[*BaselineEntry]bool)
+	}
+	b.analyzed[f.Name()] = true
+	return func(d sqlcheck.Diagnostic) bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		fp := fingerprint(f, d)
+		for _, e := range b.Entries {
+			if !b.matched[e] && *e == *fp {
+				b.matched[e] = true
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// fingerprint returns the fingerprint of the given diagnostic. The statement
+// text is used instead of its position, to keep the fingerprint stable when
+// other statements are added or removed from the file.
+func fingerprint(f *sqlcheck.File, d sqlcheck.Diagnostic) *BaselineEntry {
+	text := d.Text
+	for _, c := range f.Changes {
+		if c.Stmt != nil && c.Stmt.Pos == d.Pos && c.Stmt.Text != "" {
+			text = c.Stmt.Text
+			break
+		}
+	}
+	h := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	return &BaselineEntry{File: f.Name(), Code: d.Code, Hash: hex.EncodeToString(h[:8])}
+}
@@ -0,0 +1,187 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+//go:build !ent
+
+package migratelint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// A FileFix describes the suggested fixes that were applied on a migration file.
+type FileFix struct {
+	Name     string               // Name of the file.
+	From, To string               // Contents before and after applying the fixes.
+	Edits    []*sqlcheck.TextEdit // Applied edits, ordered by their position.
+	Skipped  int                  // Number of edits that were skipped, as they overlap with others.
+}
+
+// Fixes returns the suggested text edits of the analyzed files applied on their
+// contents. Identical edits are applied once, and edits that overlap previous
+// ones are skipped. Files without text edits are not returned.
+func (r *SummaryReport) Fixes() []*FileFix {
+	var fixes []*FileFix
+	for _, f := range r.Files {
+		// Files that were not analyzed (e.g., failed to load).
+		if f.File == nil {
+			continue
+		}
+		var edits []*sqlcheck.TextEdit
+		for _, x := range f.allFixes() {
+			if e := x.TextEdit; e != nil && e.Line > 0 && e.End >= e.Line && !slices.ContainsFunc(edits, func(o *sqlcheck.TextEdit) bool { return *o == *e }) {
+				edits = append(edits, e)
+			}
+		}
+		if len(edits) == 0 {
+			continue
+		}
+		fix := &FileFix{Name: f.Name, From: f.Text}
+		fix.To, fix.Edits, fix.Skipped = applyEdits(f.Text, edits)
+		if len(fix.Edits) > 0 {
+			fixes = append(fixes, fix)
+		}
+	}
+	return fixes
+}
+
+// allFixes returns all suggested fixes of the file, including ones without a message.
+func (f *FileReport) allFixes() []sqlcheck.SuggestedFix {
+	var fixes []sqlcheck.SuggestedFix
+	for _, r := range f.Reports {
+		fixes = append(fixes, r.SuggestedFixes...)
+		for _, d := range r.Diagnostics {
+			fixes = append(fixes, d.SuggestedFixes...)
+		}
+	}
+	return fixes
+}
+
+// ApplyFixes writes the fixed files to the migration directory and updates its sum file.
+func ApplyFixes(dir migrate.Dir, fixes []*FileFix) error {
+	for _, f := range fixes {
+		if err := dir.WriteFile(f.Name, []byte(f.To)); err != nil {
+			return fmt.Errorf("writing fixed file %q: %w", f.Name, err)
+		}
+	}
+	sum, err := dir.Checksum()
+	if err != nil {
+		return err
+	}
+	return migrate.WriteSumFile(dir, sum)
+}
+
+// diffContext is the number of context lines in unified diffs.
+const diffContext = 3
+
+// Diff returns the unified diff between the file contents before and after applying
+// the fixes. The given path is used as the file name in the diff header.
+func (f *FileFix) Diff(path string) string {
+	var (
+		b     strings.Builder
+		from  = splitLines(f.From)
+		delta int
+	)
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+	for i := 0; i < len(f.Edits); {
+		// Group edits whose context lines overlap into one hunk.
+		j := i + 1
+		for j < len(f.Edits) && f.Edits[j].Line-1-f.Edits[j-1].End <= 2*diffContext {
+			j++
+		}
+		var (
+			hunk       []string
+			start      = max(0, f.Edits[i].Line-1-diffContext)
+			end        = min(len(from), f.Edits[j-1].End+diffContext)
+			pos        = start
+			oldN, newN int
+		)
+		for _, e := range f.Edits[i:j] {
+			for ; pos < e.Line-1; pos++ {
+				hunk = append(hunk, " "+from[pos])
+			}
+			// Lines that were not changed by the edit are printed as context.
+			del, ins := from[e.Line-1:e.End], splitLines(e.NewText)
+			for len(del) > 0 && len(ins) > 0 && del[0] == ins[0] {
+				hunk = append(hunk, " "+del[0])
+				del, ins = del[1:], ins[1:]
+			}
+			var suffix []string
+			for len(del) > 0 && len(ins) > 0 && del[len(del)-1] == ins[len(ins)-1] {
+				suffix = append([]string{" " + del[len(del)-1]}, suffix...)
+				del, ins = del[:len(del)-1], ins[:len(ins)-1]
+			}
+			for _, l := range del {
+				hunk = append(hunk, "-"+l)
+			}
+			for _, l := range ins {
+				hunk = append(hunk, "+"+l)
+			}
+			hunk = append(hunk, suffix...)
+			oldN, newN, pos = oldN+len(del), newN+len(ins), e.End
+		}
+		for ; pos < end; pos++ {
+			hunk = append(hunk, " "+from[pos])
+		}
+		ctx := len(hunk) - oldN - newN
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(start, ctx+oldN), hunkRange(start+delta, ctx+newN))
+		for _, l := range hunk {
+			b.WriteString(l)
+			b.WriteByte('\n')
+		}
+		delta += newN - oldN
+		i = j
+	}
+	return b.String()
+}
+
+// hunkRange formats the range of a unified diff hunk.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// applyEdits applies the given line-based edits on the text. Edits that overlap
+// with edits positioned before them are skipped.
+func applyEdits(text string, edits []*sqlcheck.TextEdit) (string, []*sqlcheck.TextEdit, int) {
+	var (
+		lines   = splitLines(text)
+		applied = make([]*sqlcheck.TextEdit, 0, len(edits))
+		skipped int
+	)
+	edits = slices.Clone(edits)
+	slices.SortStableFunc(edits, func(a, b *sqlcheck.TextEdit) int { return a.Line - b.Line })
+	for _, e := range edits {
+		if e.End > len(lines) || len(applied) > 0 && e.Line <= applied[len(applied)-1].End {
+			skipped++
+			continue
+		}
+		applied = append(applied, e)
+	}
+	// Edits are applied from bottom to top to keep the positions of the previous ones.
+	for i := len(applied) - 1; i >= 0; i-- {
+		e := applied[i]
+		lines = slices.Replace(lines, e.Line-1, e.End, splitLines(e.NewText)...)
+	}
+	fixed := strings.Join(lines, "\n")
+	if strings.HasSuffix(text, "\n") {
+		fixed += "\n"
+	}
+	return fixed, applied, skipped
+}
+
+// splitLines splits the text into lines, ignoring the trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
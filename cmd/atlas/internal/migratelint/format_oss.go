@@ -0,0 +1,352 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+//go:build !ent
+
+package migratelint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// A list of built-in report formats.
+const (
+	FormatSARIF  = "sarif"
+	FormatJUnit  = "junit"
+	FormatGitHub = "github-annotations"
+)
+
+type (
+	// SARIFWriter writes the summary report in the SARIF 2.1.0 format,
+	// used by code scanning dashboards. See: https://sarifweb.azurewebsites.net.
+	SARIFWriter struct {
+		W io.Writer
+	}
+
+	// JUnitWriter writes the summary report in the JUnit XML format. Each diagnostic
+	// is reported as a failed test case, and each file as a test suite.
+	JUnitWriter struct {
+		W io.Writer
+	}
+
+	// GitHubWriter writes the summary report as GitHub Actions workflow commands,
+	// that are displayed as annotations on pull requests.
+	GitHubWriter struct {
+		W io.Writer
+	}
+)
+
+// ReportWriterFor returns the built-in ReportWriter for the given format, if exists.
+func ReportWriterFor(format string, w io.Writer) (ReportWriter, bool) {
+	switch format {
+	case FormatSARIF:
+		return &SARIFWriter{W: w}, true
+	case FormatJUnit:
+		return &JUnitWriter{W: w}, true
+	case FormatGitHub:
+		return &GitHubWriter{W: w}, true
+	default:
+		return nil, false
+	}
+}
+
+// lintResult describes a single diagnostic or error found in the analysis.
+type lintResult struct {
+	file, code, text, level string
+	line                    int
+	fixes                   []sqlcheck.SuggestedFix
+}
+
+// results flattens the summary report into a list of results.
+func (r *SummaryReport) results() []*lintResult {
+	var rs []*lintResult
+	collect := func(f *FileReport) {
+		errs := slices.Clone(f.Errors)
+		if len(errs) == 0 && f.Error != "" {
+			errs = []string{f.Error}
+		}
+		name := r.filePath(f.Name)
+		for _, rp := range f.Reports {
+			level := SeverityWarning
+			// Reports that caused the analysis to fail.
+			if slices.Contains(errs, rp.Text) {
+				level = SeverityError
+				errs = slices.DeleteFunc(errs, func(s string) bool { return s == rp.Text })
+			}
+			for _, d := range rp.Diagnostics {
+				res := &lintResult{file: name, code: d.Code, text: d.Text, level: level, fixes: d.SuggestedFixes}
+				// Diagnostics with an effective severity set by the runner.
+				if d.Severity != "" {
+					res.level = d.Severity
+				}
+				if d.Pos >= 0 && d.Pos <= len(f.Text) {
+					res.line = f.Line(d.Pos)
+				}
+				rs = append(rs, res)
+			}
+		}
+		// Errors that are not associated with a report.
+		for _, e := range errs {
+			rs = append(rs, &lintResult{file: name, text: e, level: SeverityError})
+		}
+	}
+	for _, s := range r.NonFileReports() {
+		collect(s.Result)
+	}
+	for _, f := range r.Files {
+		collect(f)
+	}
+	return rs
+}
+
+// filePath returns the path of the file relative to the working directory.
+func (r *SummaryReport) filePath(name string) string {
+	if name == "" || r.Env.Dir == "" {
+		return name
+	}
+	return path.Join(filepath.ToSlash(r.Env.Dir), name)
+}
+
+// SARIF log format. Only the fields used by Atlas are defined.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool      `json:"tool"`
+		Results []*sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver struct {
+			Name           string       `json:"name"`
+			InformationURI string       `json:"informationUri"`
+			Rules          []*sarifRule `json:"rules,omitempty"`
+		} `json:"driver"`
+	}
+	sarifRule struct {
+		ID      string `json:"id"`
+		HelpURI string `json:"helpUri"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId,omitempty"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations,omitempty"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+	sarifLocation struct {
+		PhysicalLocation struct {
+			ArtifactLocation sarifArtifact `json:"artifactLocation"`
+			Region           *sarifRegion  `json:"region,omitempty"`
+		} `json:"physicalLocation"`
+	}
+	sarifArtifact struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+		EndLine   int `json:"endLine,omitempty"`
+	}
+	sarifFix struct {
+		Description     sarifMessage     `json:"description"`
+		ArtifactChanges []sarifArtChange `json:"artifactChanges"`
+	}
+	sarifArtChange struct {
+		ArtifactLocation sarifArtifact      `json:"artifactLocation"`
+		Replacements     []sarifReplacement `json:"replacements"`
+	}
+	sarifReplacement struct {
+		DeletedRegion   sarifRegion   `json:"deletedRegion"`
+		InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+	}
+)
+
+// WriteReport implements ReportWriter.
+func (w *SARIFWriter) WriteReport(r *SummaryReport) error {
+	run := sarifRun{Results: make([]*sarifResult, 0)}
+	run.Tool.Driver.Name = "atlas"
+	run.Tool.Driver.InformationURI = "https://atlasgo.io"
+	for _, res := range r.results() {
+		sr := &sarifResult{RuleID: res.code, Level: res.level, Message: sarifMessage{Text: res.text}}
+		if res.code != "" && !slices.ContainsFunc(run.Tool.Driver.Rules, func(r *sarifRule) bool { return r.ID == res.code }) {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: res.code, HelpURI: "https://atlasgo.io/lint/analyzers#" + res.code})
+		}
+		if res.file != "" {
+			var loc sarifLocation
+			loc.PhysicalLocation.ArtifactLocation.URI = res.file
+			if res.line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: res.line}
+			}
+			sr.Locations = append(sr.Locations, loc)
+		}
+		for _, f := range res.fixes {
+			// Fixes without text edits cannot be applied automatically.
+			if f.TextEdit == nil || res.file == "" {
+				continue
+			}
+			sr.Fixes = append(sr.Fixes, sarifFix{
+				Description: sarifMessage{Text: f.Message},
+				ArtifactChanges: []sarifArtChange{{
+					ArtifactLocation: sarifArtifact{URI: res.file},
+					Replacements: []sarifReplacement{{
+						DeletedRegion:   sarifRegion{StartLine: f.TextEdit.Line, EndLine: f.TextEdit.End},
+						InsertedContent: &sarifMessage{Text: f.TextEdit.NewText},
+					}},
+				}},
+			})
+		}
+		run.Results = append(run.Results, sr)
+	}
+	enc := json.NewEncoder(w.W)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// JUnit XML format.
+type (
+	junitSuites struct {
+		XMLName  xml.Name      `xml:"testsuites"`
+		Name     string        `xml:"name,attr"`
+		Tests    int           `xml:"tests,attr"`
+		Failures int           `xml:"failures,attr"`
+		Errors   int           `xml:"errors,attr"`
+		Time     string        `xml:"time,attr"`
+		Suites   []*junitSuite `xml:"testsuite"`
+	}
+	junitSuite struct {
+		Name     string       `xml:"name,attr"`
+		Tests    int          `xml:"tests,attr"`
+		Failures int          `xml:"failures,attr"`
+		Errors   int          `xml:"errors,attr"`
+		Cases    []*junitCase `xml:"testcase"`
+	}
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+		Error     *junitFailure `xml:"error,omitempty"`
+	}
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr,omitempty"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// WriteReport implements ReportWriter.
+func (w *JUnitWriter) WriteReport(r *SummaryReport) error {
+	var (
+		suites = &junitSuites{Name: "atlas migrate lint", Time: fmt.Sprintf("%.3f", r.End.Sub(r.Start).Seconds())}
+		byFile = make(map[string]*junitSuite)
+	)
+	suiteOf := func(name string) *junitSuite {
+		if s, ok := byFile[name]; ok {
+			return s
+		}
+		s := &junitSuite{Name: name}
+		// Reports that are not related to a specific file.
+		if name == "" {
+			s.Name = "migration directory"
+		}
+		byFile[name] = s
+		suites.Suites = append(suites.Suites, s)
+		return s
+	}
+	// Files without findings are reported as passed test cases.
+	for _, f := range r.Files {
+		suiteOf(r.filePath(f.Name))
+	}
+	for _, res := range r.results() {
+		var (
+			s = suiteOf(res.file)
+			c = &junitCase{Name: res.code, ClassName: res.file}
+			f = &junitFailure{Message: res.text, Type: res.code, Text: res.text}
+		)
+		if c.Name == "" {
+			c.Name = res.level
+		}
+		if res.line > 0 {
+			f.Text = fmt.Sprintf("%s:%d: %s", res.file, res.line, res.text)
+		}
+		if res.level == SeverityError && res.code == "" {
+			c.Error = f
+			s.Errors++
+		} else {
+			c.Failure = f
+			s.Failures++
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	for _, s := range suites.Suites {
+		if len(s.Cases) == 0 {
+			s.Cases = append(s.Cases, &junitCase{Name: "lint", ClassName: s.Name})
+		}
+		s.Tests = len(s.Cases)
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Errors += s.Errors
+	}
+	if _, err := io.WriteString(w.W, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w.W)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.W, "\n")
+	return err
+}
+
+// WriteReport implements ReportWriter.
+func (w *GitHubWriter) WriteReport(r *SummaryReport) error {
+	for _, res := range r.results() {
+		var props []string
+		if res.file != "" {
+			props = append(props, "file="+ghEscapeProp(res.file))
+		}
+		if res.line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", res.line))
+		}
+		if res.code != "" {
+			props = append(props, "title="+ghEscapeProp(res.code))
+		}
+		cmd := "::" + res.level
+		if len(props) > 0 {
+			cmd += " " + strings.Join(props, ",")
+		}
+		if _, err := fmt.Fprintf(w.W, "%s::%s\n", cmd, ghEscapeData(res.text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ghEscapeData escapes the message of a GitHub workflow command.
+func ghEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// ghEscapeProp escapes a property value of a GitHub workflow command.
+func ghEscapeProp(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,289 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migratelint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqltool"
+)
+
+// Hygiene is a DirAnalyzer that checks the migration files for problems that
+// otherwise surface only when they are applied: unknown or misspelled directives,
+// conflicting txmode directives, empty files, invalid versions, non-idempotent
+// statements and missing down files. For example:
+//
+//	lint {
+//	  hygiene {
+//	    error          = true
+//	    idempotent     = true
+//	    version_format = "timestamp"
+//	  }
+//	}
+type Hygiene struct {
+	sqlcheck.Options
+
+	// Idempotent indicates the migration files are expected to be re-runnable,
+	// and CREATE statements must use IF NOT EXISTS (or OR REPLACE).
+	Idempotent bool
+
+	// VersionFormat is the format the file versions are expected to match.
+	// An empty value disables the check. See VersionFormatTimestamp.
+	VersionFormat string
+}
+
+// VersionFormatTimestamp checks the file versions with migrate.CheckVersion.
+const VersionFormatTimestamp = "timestamp"
+
+// Directives lists the names of the atlas directives that are recognized in migration files.
+var Directives = []string{"assert", "checkpoint", "delimiter", "import", "nolint", "stmt-timeout", "sum", "timeout", "txmode", "txtar"}
+
+// NewHygiene creates a new Hygiene analyzer with the given options.
+func NewHygiene(r *schemahcl.Resource) (*Hygiene, error) {
+	az := &Hygiene{}
+	r, ok := r.Resource(az.Name())
+	if !ok {
+		return az, nil
+	}
+	if err := r.As(&az.Options); err != nil {
+		return nil, fmt.Errorf("migratelint: parsing hygiene check options: %w", err)
+	}
+	if a, ok := az.Remain().Attr("idempotent"); ok {
+		b, err := a.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("migratelint: parsing hygiene idempotent option: %w", err)
+		}
+		az.Idempotent = b
+	}
+	if a, ok := az.Remain().Attr("version_format"); ok {
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("migratelint: parsing hygiene version_format option: %w", err)
+		}
+		if s != VersionFormatTimestamp {
+			return nil, fmt.Errorf("migratelint: unknown hygiene version_format %q, expect %q", s, VersionFormatTimestamp)
+		}
+		az.VersionFormat = s
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeUnknownDirective = sqlcheck.Code("HY101")
+	codeTxMode           = sqlcheck.Code("HY102")
+	codeEmptyFile        = sqlcheck.Code("HY103")
+	codeVersionFormat    = sqlcheck.Code("HY104")
+	codeNotIdempotent    = sqlcheck.Code("HY105")
+	codeMissingDown      = sqlcheck.Code("HY106")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Hygiene) Name() string {
+	return "hygiene"
+}
+
+// AnalyzeDir implements DirAnalyzer.
+func (a *Hygiene) AnalyzeDir(_ context.Context, p *DirPass) error {
+	var (
+		f     = p.File
+		diags = a.directives(f)
+	)
+	stmts, err := f.StmtDecls()
+	// Scanning errors are reported by the replay step.
+	if err == nil && len(stmts) == 0 && len(fileDirectives(f)) == 0 {
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeEmptyFile,
+			Text: fmt.Sprintf("File %q contains no statements", f.Name()),
+		})
+	}
+	if a.VersionFormat == VersionFormatTimestamp {
+		if err := migrate.CheckVersion(f.Version()); err != nil {
+			diags = append(diags, sqlcheck.Diagnostic{
+				Code: codeVersionFormat,
+				Text: fmt.Sprintf("Version %q of file %q does not match the timestamp format YYYYMMDDHHMMSS", f.Version(), f.Name()),
+			})
+		}
+	}
+	if a.Idempotent {
+		for _, s := range stmts {
+			if m := reCreate.FindStringSubmatch(s.Text); m != nil && !reIdempotent.MatchString(m[0]) {
+				diags = append(diags, sqlcheck.Diagnostic{
+					Code: codeNotIdempotent,
+					Pos:  s.Pos,
+					Text: fmt.Sprintf("CREATE %s statement is not idempotent, use IF NOT EXISTS", strings.ToUpper(m[1])),
+				})
+			}
+		}
+	}
+	if d, ok := missingDown(p.Dir, f); ok {
+		diags = append(diags, d)
+	}
+	if len(diags) == 0 {
+		return nil
+	}
+	const reportText = "migration file hygiene issues detected"
+	p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+	if a.Error != nil && *a.Error {
+		return errors.New(reportText)
+	}
+	return nil
+}
+
+var (
+	// Directive comments, such as "-- atlas:txmode none".
+	reDirective = regexp.MustCompile(`^\s*(?:--|#|/\*)\s*atlas:(\w+(?:-\w+)*)`)
+	// Head of CREATE statements, up to the object name.
+	reCreate     = regexp.MustCompile(`(?is)^CREATE\s+(?:(?:OR\s+REPLACE|UNIQUE|TEMP|TEMPORARY|MATERIALIZED|RECURSIVE)\s+)*(\w+)\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\b)?`)
+	reIdempotent = regexp.MustCompile(`(?is)\bIF\s+NOT\s+EXISTS\b|\bOR\s+REPLACE\b`)
+	// Down sections of goose and dbmate files.
+	reGooseDown  = regexp.MustCompile(`(?m)^--\s*\+goose\s+Down\b`)
+	reDBMateDown = regexp.MustCompile(`(?m)^--\s*migrate:down\b`)
+)
+
+// directives reports unknown directives, and txmode directives that
+// conflict with each other or are not placed at the top of the file.
+func (a *Hygiene) directives(f migrate.File) []sqlcheck.Diagnostic {
+	var (
+		diags []sqlcheck.Diagnostic
+		pos   int
+		txPos = -1
+	)
+	for _, line := range strings.SplitAfter(string(f.Bytes()), "\n") {
+		if m := reDirective.FindStringSubmatch(line); m != nil {
+			switch name := m[1]; {
+			case name == "txmode":
+				if txPos == -1 {
+					txPos = pos
+				}
+			case !slices.Contains(Directives, name):
+				d := sqlcheck.Diagnostic{
+					Code: codeUnknownDirective,
+					Pos:  pos,
+					Text: fmt.Sprintf("Unknown directive %q", "atlas:"+name),
+				}
+				if s, ok := closest(name, Directives); ok {
+					d.Text += fmt.Sprintf(". Did you mean %q?", "atlas:"+s)
+				}
+				diags = append(diags, d)
+			}
+		}
+		pos += len(line)
+	}
+	if txPos == -1 {
+		return diags
+	}
+	ds := fileDirectives(f)["txmode"]
+	switch {
+	case len(ds) == 0:
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeTxMode,
+			Pos:  txPos,
+			Text: "Directive \"atlas:txmode\" is ignored, as it is not a file directive. File directives must be separated from the statements by an empty line",
+		})
+	case len(slices.Compact(slices.Sorted(slices.Values(ds)))) > 1:
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeTxMode,
+			Pos:  txPos,
+			Text: fmt.Sprintf("Conflicting txmode directives %q", ds),
+		})
+	case len(ds) > 1:
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeTxMode,
+			Pos:  txPos,
+			Text: fmt.Sprintf("Multiple txmode directives %q", ds),
+		})
+	case ds[0] != "none" && ds[0] != "file":
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeTxMode,
+			Pos:  txPos,
+			Text: fmt.Sprintf("Invalid txmode %q in file directive, expect \"none\" or \"file\"", ds[0]),
+		})
+	}
+	return diags
+}
+
+// fileDirectives returns the known file directives of the given file, keyed by their names.
+func fileDirectives(f migrate.File) map[string][]string {
+	l, ok := f.(interface{ Directive(string) []string })
+	if !ok {
+		return nil
+	}
+	m := make(map[string][]string)
+	for _, name := range Directives {
+		if ds := l.Directive(name); len(ds) > 0 {
+			m[name] = ds
+		}
+	}
+	return m
+}
+
+// missingDown reports if the given file has no down migration, in
+// directory formats that define down migrations for each version.
+func missingDown(dir migrate.Dir, f migrate.File) (sqlcheck.Diagnostic, bool) {
+	var text string
+	switch dir := dir.(type) {
+	case *sqltool.GolangMigrateDir:
+		down := strings.TrimSuffix(f.Name(), ".up.sql") + ".down.sql"
+		if _, err := fs.Stat(dir, down); !errors.Is(err, fs.ErrNotExist) {
+			return sqlcheck.Diagnostic{}, false
+		}
+		text = fmt.Sprintf("Migration file %q has no down file %q", f.Name(), down)
+	case *sqltool.GooseDir:
+		if reGooseDown.Match(f.Bytes()) {
+			return sqlcheck.Diagnostic{}, false
+		}
+		text = fmt.Sprintf("Migration file %q has no down section (-- +goose Down)", f.Name())
+	case *sqltool.DBMateDir:
+		if reDBMateDown.Match(f.Bytes()) {
+			return sqlcheck.Diagnostic{}, false
+		}
+		text = fmt.Sprintf("Migration file %q has no down section (-- migrate:down)", f.Name())
+	default:
+		return sqlcheck.Diagnostic{}, false
+	}
+	return sqlcheck.Diagnostic{Code: codeMissingDown, Text: text}, true
+}
+
+// closest returns the closest name to s, within an edit distance of 2.
+func closest(s string, names []string) (string, bool) {
+	best, dist := "", 3
+	for _, n := range names {
+		if d := editDistance(s, n); d < dist {
+			best, dist = n, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
@@ -37,6 +37,33 @@ type (
 		From, To *schema.Realm    // Current and desired schema.
 		Files    []*sqlcheck.File // Files for moving from current to desired state.
 	}
+
+	// A DirAnalyzer inspects the new migration files of a directory before they are
+	// replayed on the dev database, and reports problems that cannot be detected from
+	// their schema changes, such as invalid directives or malformed file names.
+	DirAnalyzer interface {
+		// AnalyzeDir analyzes the file of the pass.
+		AnalyzeDir(context.Context, *DirPass) error
+	}
+
+	// DirPass represents a directory analysis pass of a single file.
+	DirPass struct {
+		// Dir is the migration directory.
+		Dir migrate.Dir
+
+		// File is the migration file to analyze.
+		File migrate.File
+
+		// Reporter is used to report diagnostics of the file.
+		Reporter sqlcheck.ReportWriter
+	}
+)
+
+// Severity levels of diagnostics.
+const (
+	SeverityError   = sqlcheck.SeverityError   // Diagnostic fails the analysis.
+	SeverityWarning = sqlcheck.SeverityWarning // Diagnostic is reported, but does not fail the analysis.
+	SeverityOff     = "off"                    // Diagnostic is dropped.
 )
 
 type (
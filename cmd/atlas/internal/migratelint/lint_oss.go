@@ -41,9 +41,25 @@ type Runner struct {
 	// Analyzers defines the analysis to run on each migration file.
 	Analyzers []sqlcheck.Analyzer
 
+	// DirAnalyzers defines the analysis to run on each new migration
+	// file in the context of its directory. e.g., file hygiene checks.
+	DirAnalyzers []DirAnalyzer
+
 	// ReportWriter writes the summary report.
 	ReportWriter ReportWriter
 
+	// Baseline holds known diagnostics that are suppressed, if set.
+	Baseline *Baseline
+
+	// Severity overrides the severity of diagnostics, keyed by their codes
+	// (e.g., DS103) or analyzer names (e.g., destructive). Codes take
+	// precedence over analyzer names. See SeverityError for valid values.
+	Severity map[string]string
+
+	// Stats holds the table statistics of the target database, if
+	// provided. It is passed to the analyzers on each pass.
+	Stats *sqlcheck.Stats
+
 	// summary report. reset on each run.
 	sum *SummaryReport
 }
@@ -140,8 +156,18 @@ func (r *Runner) summary(ctx context.Context) error {
 	l := &DevLoader{Dev: r.Dev}
 	diff, err := l.LoadChanges(ctx, base, feat)
 	if err != nil {
-		if fr := (&FileError{}); errors.As(err, &fr) {
-			r.sum.Files = append(r.sum.Files, &FileReport{Name: fr.File, Error: err.Error()})
+		if fe := (&FileError{}); errors.As(err, &fe) {
+			fr := &FileReport{Name: fe.File, Error: err.Error()}
+			// Directory checks run on files that failed to load,
+			// as their findings might explain the failure.
+			if i := slices.IndexFunc(feat, func(f migrate.File) bool { return f.Name() == fe.File }); i != -1 {
+				f := &sqlcheck.File{File: feat[i]}
+				if nl := nolintRules(f); !nl.ignored {
+					nl.known, nl.severity = r.Baseline.suppress(f), r.Severity
+					r.analyzeDir(ctx, f, nl, fr)
+				}
+			}
+			r.sum.Files = append(r.sum.Files, fr)
 		}
 		return r.sum.StepError(StepLoadChanges, "Failed loading changes on dev database", err)
 	}
@@ -163,7 +189,10 @@ func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
 		if nl.ignored {
 			continue
 		}
+		nl.known, nl.severity = r.Baseline.suppress(f), r.Severity
+		es = r.analyzeDir(ctx, f, nl, fr)
 		for _, az := range r.Analyzers {
+			n := len(fr.Reports)
 			err := func(az sqlcheck.Analyzer) (rerr error) {
 				defer func() {
 					if rc := recover(); rc != nil {
@@ -177,16 +206,18 @@ func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
 				return az.Analyze(ctx, &sqlcheck.Pass{
 					File:     f,
 					Dev:      r.Dev,
+					Stats:    r.Stats,
 					Reporter: nl.reporterFor(fr, az),
 				})
 			}(az)
+			err = severity(r.Severity, az, fr.Reports[n:], err)
 			// If the last report was skipped,
 			// skip emitting its error.
 			if err != nil && !nl.skipped {
 				es = append(es, err.Error())
 			}
 		}
-		fr.Error = strings.Join(es, "; ")
+		fr.Errors, fr.Error = es, strings.Join(es, "; ")
 		r.sum.Files = append(r.sum.Files, fr)
 		r.sum.StepResult(
 			fmt.Sprintf(StepAnalyzeFile, f.Name()),
@@ -197,6 +228,128 @@ func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
 	return nil
 }
 
+// analyzeDir runs the directory analyzers on the given file, attaches their reports
+// to the file report, and returns the errors of the analyzers.
+func (r *Runner) analyzeDir(ctx context.Context, f *sqlcheck.File, nl *skipRules, fr *FileReport) []string {
+	var es []string
+	for _, az := range r.DirAnalyzers {
+		n := len(fr.Reports)
+		err := az.AnalyzeDir(ctx, &DirPass{
+			Dir:      r.Dir,
+			File:     f.File,
+			Reporter: nl.reporterFor(fr, az),
+		})
+		if err = severity(r.Severity, az, fr.Reports[n:], err); err != nil && !nl.skipped {
+			es = append(es, err.Error())
+		}
+	}
+	return es
+}
+
+// ApplySeverity removes the diagnostics that were turned off in the given severity
+// configuration from the reports of the analyzer, and sets the effective severity of
+// the rest. It returns the non-empty reports and the analysis error, if any.
+func ApplySeverity(m map[string]string, az sqlcheck.Analyzer, reports []sqlcheck.Report, err error) ([]sqlcheck.Report, error) {
+	name := analyzerName(az)
+	kept := make([]sqlcheck.Report, 0, len(reports))
+	for _, rp := range reports {
+		rp.Diagnostics = slices.DeleteFunc(slices.Clone(rp.Diagnostics), func(d sqlcheck.Diagnostic) bool {
+			return severityOf(m, name, d.Code) == SeverityOff
+		})
+		if len(rp.Diagnostics) > 0 {
+			kept = append(kept, rp)
+		}
+	}
+	// Errors caused by reports that were turned off are dropped,
+	// but runtime errors are propagated.
+	if len(kept) == 0 {
+		if err != nil && slices.ContainsFunc(reports, func(rp sqlcheck.Report) bool { return rp.Text == err.Error() }) {
+			err = nil
+		}
+		return nil, err
+	}
+	return kept, severity(m, az, kept, err)
+}
+
+// severity sets the effective severity of the diagnostics reported by the analyzer, and
+// returns the analysis error in case any of them is an error. By default, diagnostics
+// are errors if their report caused the analyzer to fail, and warnings otherwise.
+func severity(m map[string]string, az any, reports []sqlcheck.Report, err error) error {
+	if len(reports) == 0 {
+		return err
+	}
+	var (
+		errs []string
+		name = analyzerName(az)
+	)
+	// Analyzers that return an error that does not match any
+	// of their report texts, fail on all of their reports.
+	matched := err != nil && slices.ContainsFunc(reports, func(rp sqlcheck.Report) bool {
+		return rp.Text == err.Error()
+	})
+	for _, rp := range reports {
+		level := SeverityWarning
+		if err != nil && (!matched || rp.Text == err.Error()) {
+			level = SeverityError
+		}
+		failed := false
+		for i := range rp.Diagnostics {
+			d := &rp.Diagnostics[i]
+			switch s := severityOf(m, name, d.Code); {
+			case s != "":
+				d.Severity = s
+			// Diagnostics that were escalated by the analyzer,
+			// for example, in case they affect a large table.
+			case d.Severity == SeverityError:
+			default:
+				d.Severity = level
+			}
+			failed = failed || d.Severity == SeverityError
+		}
+		if !failed {
+			continue
+		}
+		text := rp.Text
+		switch {
+		case level == SeverityError:
+			text = err.Error()
+		case text == "" && len(rp.Diagnostics) > 0:
+			text = rp.Diagnostics[0].Text
+		}
+		if !slices.Contains(errs, text) {
+			errs = append(errs, text)
+		}
+	}
+	// Errors that do not match any report, such as runtime errors,
+	// are propagated regardless of the severity of the diagnostics.
+	if err != nil && !matched && !slices.Contains(errs, err.Error()) {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// severityOf returns the configured severity of the diagnostic code, or its analyzer.
+func severityOf(m map[string]string, name, code string) string {
+	if s, ok := m[code]; ok && code != "" {
+		return s
+	}
+	if name != "" {
+		return m[name]
+	}
+	return ""
+}
+
+// analyzerName returns the name of the analyzer, if it is named.
+func analyzerName(az any) string {
+	if n, ok := az.(sqlcheck.NamedAnalyzer); ok {
+		return n.Name()
+	}
+	return ""
+}
+
 var (
 	// TemplateFuncs are global functions available in templates.
 	TemplateFuncs = template.FuncMap{
@@ -435,6 +588,10 @@ type (
 		Error   string            `json:"Error,omitempty"`   // File specific error.
 		Change  FileChange        `json:"Change,omitempty"`  // Change of the file.
 
+		// Errors holds the errors that are joined in Error, in case the
+		// analysis of the file failed with more than one error.
+		Errors []string `json:"-"`
+
 		// Logging only info.
 		Start          time.Time `json:"-"` // Start time of the analysis.
 		End            time.Time `json:"-"` // End time of the analysis.
@@ -658,16 +815,19 @@ func nolintRules(f *sqlcheck.File) *skipRules {
 }
 
 type skipRules struct {
-	pos2rules map[int][]string // statement positions to rules
-	ignored   bool             // file is ignored. i.e., no analysis is performed
-	skipped   bool             // if the last report was skipped by the rules
+	pos2rules map[int][]string               // statement positions to rules
+	ignored   bool                           // file is ignored. i.e., no analysis is performed
+	skipped   bool                           // if the last report was skipped by the rules
+	known     func(sqlcheck.Diagnostic) bool // reports if a diagnostic is known (baseline)
+	severity  map[string]string              // configured severity of codes and analyzers
 }
 
-func (s *skipRules) reporterFor(rw sqlcheck.ReportWriter, az sqlcheck.Analyzer) sqlcheck.ReportWriter {
+func (s *skipRules) reporterFor(rw sqlcheck.ReportWriter, az any) sqlcheck.ReportWriter {
 	return sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
 		var (
 			ds     = make([]sqlcheck.Diagnostic, 0, len(r.Diagnostics))
 			az, ok = az.(sqlcheck.NamedAnalyzer)
+			name   = analyzerName(az)
 		)
 		for _, d := range r.Diagnostics {
 			switch rules := s.pos2rules[d.Pos]; {
@@ -678,7 +838,11 @@ func (s *skipRules) reporterFor(rw sqlcheck.ReportWriter, az sqlcheck.Analyzer)
 				// Match a specific code/diagnostic. e.g. atlas:nolint DS101.
 				slices.Contains(rules, d.Code),
 				// Skip the entire analyzer (class of changes).
-				ok && slices.Contains(rules, az.Name()):
+				ok && slices.Contains(rules, az.Name()),
+				// Known diagnostics that were recorded in the baseline.
+				s.known != nil && s.known(d),
+				// Codes or analyzers that were turned off.
+				severityOf(s.severity, name, d.Code) == SeverityOff:
 			default:
 				ds = append(ds, d)
 			}
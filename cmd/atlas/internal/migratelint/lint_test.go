@@ -5,17 +5,23 @@
 package migratelint_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/migratelint"
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
 	_ "ariga.io/atlas/sql/sqlite"
+	"ariga.io/atlas/sql/sqltool"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
@@ -130,3 +136,176 @@ func TestLatestChanges(t *testing.T) {
 	require.Equal(t, files[:1], base)
 	require.Equal(t, files[1:], feat)
 }
+
+func TestSummaryReport_Fixes(t *testing.T) {
+	const text = "CREATE TABLE t(c int);\nCREATE TABLE u(c int);\nCREATE TABLE v(c int);\n"
+	f := &sqlcheck.File{File: migrate.NewLocalFile("1.sql", []byte(text))}
+	fr := migratelint.NewFileReport(f)
+	fr.WriteReport(sqlcheck.Report{
+		Diagnostics: []sqlcheck.Diagnostic{
+			{
+				Pos: 23,
+				SuggestedFixes: []sqlcheck.SuggestedFix{
+					{TextEdit: &sqlcheck.TextEdit{Line: 2, End: 3, NewText: "CREATE TABLE uv(c int);"}},
+				},
+			},
+			{
+				Pos: 46,
+				SuggestedFixes: []sqlcheck.SuggestedFix{
+					// Overlaps with the previous edit.
+					{TextEdit: &sqlcheck.TextEdit{Line: 3, End: 3, NewText: "CREATE TABLE w(c int);"}},
+				},
+			},
+			{
+				Pos: 0,
+				SuggestedFixes: []sqlcheck.SuggestedFix{
+					{TextEdit: &sqlcheck.TextEdit{Line: 1, End: 1, NewText: "-- atlas:txmode none\n\nCREATE TABLE t(c int);"}},
+					// Identical edits are applied once.
+					{TextEdit: &sqlcheck.TextEdit{Line: 1, End: 1, NewText: "-- atlas:txmode none\n\nCREATE TABLE t(c int);"}},
+				},
+			},
+		},
+	})
+	sum := &migratelint.SummaryReport{Files: []*migratelint.FileReport{fr}}
+	fixes := sum.Fixes()
+	require.Len(t, fixes, 1)
+	require.Equal(t, "1.sql", fixes[0].Name)
+	require.Len(t, fixes[0].Edits, 2)
+	require.Equal(t, 1, fixes[0].Skipped)
+	require.Equal(t, "-- atlas:txmode none\n\nCREATE TABLE t(c int);\nCREATE TABLE uv(c int);\n", fixes[0].To)
+	require.Equal(t, `--- a/1.sql
++++ b/1.sql
+@@ -1,3 +1,4 @@
++-- atlas:txmode none
++
+ CREATE TABLE t(c int);
+-CREATE TABLE u(c int);
+-CREATE TABLE v(c int);
++CREATE TABLE uv(c int);
+`, fixes[0].Diff("1.sql"))
+}
+
+func TestJUnitWriter_FileErrors(t *testing.T) {
+	f := &sqlcheck.File{File: migrate.NewLocalFile("1.sql", []byte("CREATE TABLE t(c int);\n"))}
+	fr := migratelint.NewFileReport(f)
+	// Error messages might contain the separator of the joined errors.
+	fr.Errors = []string{"executing statement: near \"x\"; syntax error", "skip crashed analyzer"}
+	fr.Error = strings.Join(fr.Errors, "; ")
+	var buf bytes.Buffer
+	w := &migratelint.JUnitWriter{W: &buf}
+	require.NoError(t, w.WriteReport(&migratelint.SummaryReport{Files: []*migratelint.FileReport{fr}}))
+	require.Contains(t, buf.String(), `tests="2" failures="0" errors="2"`)
+	require.Contains(t, buf.String(), `<error message="executing statement: near &#34;x&#34;; syntax error"`)
+}
+
+func TestApplySeverity(t *testing.T) {
+	var (
+		sev     = map[string]string{"DS102": migratelint.SeverityWarning}
+		az      = sqlcheck.AnalyzerFunc(func(context.Context, *sqlcheck.Pass) error { return nil })
+		reports = []sqlcheck.Report{{Text: "destructive changes detected", Diagnostics: []sqlcheck.Diagnostic{{Code: "DS102"}}}}
+	)
+	// Downgraded reports do not fail the analysis.
+	kept, err := migratelint.ApplySeverity(sev, az, reports, errors.New("destructive changes detected"))
+	require.NoError(t, err)
+	require.Len(t, kept, 1)
+	require.Equal(t, migratelint.SeverityWarning, kept[0].Diagnostics[0].Severity)
+
+	// Errors that do not match any report are propagated.
+	kept, err = migratelint.ApplySeverity(sev, az, reports, errors.New("connection refused"))
+	require.EqualError(t, err, "connection refused")
+	require.Len(t, kept, 1)
+	require.Equal(t, migratelint.SeverityWarning, kept[0].Diagnostics[0].Severity)
+
+	// Reports that were turned off are dropped, but runtime errors are not.
+	sev["DS102"] = migratelint.SeverityOff
+	kept, err = migratelint.ApplySeverity(sev, az, reports, errors.New("destructive changes detected"))
+	require.NoError(t, err)
+	require.Empty(t, kept)
+	kept, err = migratelint.ApplySeverity(sev, az, reports, errors.New("connection refused"))
+	require.EqualError(t, err, "connection refused")
+	require.Empty(t, kept)
+}
+
+func TestHygiene(t *testing.T) {
+	var (
+		reports []sqlcheck.Report
+		analyze = func(az *migratelint.Hygiene, dir migrate.Dir, f migrate.File) error {
+			reports = nil
+			return az.AnalyzeDir(context.Background(), &migratelint.DirPass{
+				Dir:  dir,
+				File: f,
+				Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+					reports = append(reports, r)
+				}),
+			})
+		}
+	)
+	az, err := migratelint.NewHygiene(&schemahcl.Resource{})
+	require.NoError(t, err)
+	require.NoError(t, analyze(az, &migrate.MemDir{}, migrate.NewLocalFile("1.sql", []byte("-- atlas:txmode none\n-- atlas:timeout 1m\n\n-- atlas:stmt-timeout 10s\nCREATE TABLE t(c int);\n"))))
+	require.Empty(t, reports)
+
+	f := migrate.NewLocalFile("1.sql", []byte("-- atlas:txmode none\n-- atlas:txmode file\n-- atlas:nolnt DS102\n\nCREATE TABLE t(c int);\n-- atlas:foo\nDROP TABLE t;\n"))
+	require.NoError(t, analyze(az, &migrate.MemDir{}, f))
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "HY101", Pos: 42, Text: `Unknown directive "atlas:nolnt". Did you mean "atlas:nolint"?`},
+		{Code: "HY101", Pos: 87, Text: `Unknown directive "atlas:foo"`},
+		{Code: "HY102", Pos: 0, Text: `Conflicting txmode directives ["none" "file"]`},
+	}, reports[0].Diagnostics)
+
+	// Directives that are attached to statements are not file directives.
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:txmode none\nCREATE TABLE t(c int);\n"))
+	require.NoError(t, analyze(az, &migrate.MemDir{}, f))
+	require.Equal(t, "HY102", reports[0].Diagnostics[0].Code)
+	require.Contains(t, reports[0].Diagnostics[0].Text, "is not a file directive")
+
+	f = migrate.NewLocalFile("1.sql", []byte("-- nothing to see here\n"))
+	require.NoError(t, analyze(az, &migrate.MemDir{}, f))
+	require.Equal(t, []sqlcheck.Diagnostic{{Code: "HY103", Text: `File "1.sql" contains no statements`}}, reports[0].Diagnostics)
+
+	// Idempotent directories with timestamp versions.
+	az, err = migratelint.NewHygiene(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "hygiene",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("error", true),
+					schemahcl.BoolAttr("idempotent", true),
+					schemahcl.StringAttr("version_format", "timestamp"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	f = migrate.NewLocalFile("1.sql", []byte("CREATE TABLE IF NOT EXISTS t(c int);\nCREATE UNIQUE INDEX i ON t(c);\nCREATE OR REPLACE VIEW v AS SELECT 1;\n"))
+	require.EqualError(t, analyze(az, &migrate.MemDir{}, f), "migration file hygiene issues detected")
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "HY104", Text: `Version "1" of file "1.sql" does not match the timestamp format YYYYMMDDHHMMSS`},
+		{Code: "HY105", Pos: 37, Text: "CREATE INDEX statement is not idempotent, use IF NOT EXISTS"},
+	}, reports[0].Diagnostics)
+	require.NoError(t, analyze(az, &migrate.MemDir{}, migrate.NewLocalFile(migrate.NewVersion()+".sql", []byte("CREATE TABLE IF NOT EXISTS t(c int);\n"))))
+	require.Empty(t, reports)
+
+	_, err = migratelint.NewHygiene(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "hygiene", Attrs: []*schemahcl.Attr{schemahcl.StringAttr("version_format", "semver")}},
+		},
+	})
+	require.EqualError(t, err, `migratelint: unknown hygiene version_format "semver", expect "timestamp"`)
+
+	// Missing down files.
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_t.up.sql"), []byte("CREATE TABLE t(c int);"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "2_t.up.sql"), []byte("DROP TABLE t;"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_t.down.sql"), []byte("DROP TABLE t;"), 0600))
+	dir, err := sqltool.NewGolangMigrateDir(p)
+	require.NoError(t, err)
+	files, err := dir.Files()
+	require.NoError(t, err)
+	az, err = migratelint.NewHygiene(&schemahcl.Resource{})
+	require.NoError(t, err)
+	require.NoError(t, analyze(az, dir, files[0]))
+	require.Empty(t, reports)
+	require.NoError(t, analyze(az, dir, files[1]))
+	require.Equal(t, []sqlcheck.Diagnostic{{Code: "HY106", Text: `Migration file "2_t.up.sql" has no down file "2_t.down.sql"`}}, reports[0].Diagnostics)
+}
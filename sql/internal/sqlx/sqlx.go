@@ -45,14 +45,20 @@ func SingleConn(ctx context.Context, conn schema.ExecQuerier) (ExecQueryCloser,
 	}); ok {
 		return opener.Conn(ctx)
 	}
+	if IsSingleConn(conn) {
+		return nopCloser{ExecQuerier: conn}, nil
+	}
+	return nil, fmt.Errorf("cannot obtain a single connection from %T", conn)
+}
+
+// IsSingleConn reports if the given ExecQuerier is bound to a single connection
+// (e.g. Tx, Conn), and session settings apply to all statements it executes.
+func IsSingleConn(conn schema.ExecQuerier) bool {
 	// Tx and Conn are bounded to a single connection.
 	// We use sql/driver.Tx to cover also custom Tx structs.
 	_, ok1 := conn.(driver.Tx)
 	_, ok2 := conn.(*sql.Conn)
-	if ok1 || ok2 {
-		return nopCloser{ExecQuerier: conn}, nil
-	}
-	return nil, fmt.Errorf("cannot obtain a single connection from %T", conn)
+	return ok1 || ok2
 }
 
 // ValidString reports if the given string is not null and valid.
@@ -271,10 +271,15 @@ const (
 	directiveDelimiter = "delimiter"
 	// atlas:checkpoint directive.
 	directiveCheckpoint = "checkpoint"
-	directivePrefixSQL  = "-- "
+	// atlas:assert directive.
+	directiveAssert = "assert"
+	// atlas:timeout and atlas:stmt-timeout directives.
+	directiveTimeout     = "timeout"
+	directiveStmtTimeout = "stmt-timeout"
+	directivePrefixSQL   = "-- "
 )
 
-var reDirective = regexp.MustCompile(`^([ -~]*)atlas:(\w+)(?: +(.+))*`)
+var reDirective = regexp.MustCompile(`^([ -~]*)atlas:(\w+(?:-\w+)*)(?: +(.+))*`)
 
 // directive searches in the content a line that matches a directive
 // with the given prefix and name. For example:
@@ -263,6 +263,8 @@ type (
 		baselineVer string             // Start the first migration after the given baseline version.
 		allowDirty  bool               // Allow start working on a non-clean database.
 		operator    string             // Revision.OperatorVersion
+		retry       *retryPolicy       // Retry policy of transient statement failures.
+		stmtTimeout time.Duration      // Default timeout of statements.
 	}
 
 	// ExecutorOption allows configuring an Executor using functional arguments.
@@ -670,6 +672,63 @@ func WithOperatorVersion(v string) ExecutorOption {
 	}
 }
 
+type (
+	// RetryClassifier reports if a statement execution error is transient,
+	// and the statement can be retried. For example, a deadlock.
+	RetryClassifier func(error) bool
+
+	// retryPolicy configures the retries of transient statement failures.
+	retryPolicy struct {
+		attempts int
+		backoff  func(int) time.Duration
+		classify RetryClassifier
+	}
+)
+
+// WithRetryPolicy configures the Executor to retry statements that failed with a transient
+// error, as reported by the classifier, up to maxAttempts executions of the statement. The
+// backoff function returns the delay before the next attempt, given the number of attempts
+// that have failed so far. A nil backoff retries immediately.
+//
+// Note, retrying a statement is safe only if it is not executed in a transaction, as some
+// databases, like PostgreSQL, abort the whole transaction on such failures, and others, like
+// MySQL, roll it back. Files that are executed in a transaction should be retried as a whole,
+// after the transaction was rolled back. Drivers provide their classifiers. For example:
+//
+//	migrate.WithRetryPolicy(3, func(n int) time.Duration {
+//		return time.Duration(n) * time.Second
+//	}, postgres.IsRetryable)
+func WithRetryPolicy(maxAttempts int, backoff func(int) time.Duration, classifier RetryClassifier) ExecutorOption {
+	return func(ex *Executor) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("sql/migrate: invalid number of retry attempts: %d", maxAttempts)
+		}
+		if classifier == nil {
+			return errors.New("sql/migrate: missing retry classifier")
+		}
+		if backoff == nil {
+			backoff = func(int) time.Duration { return 0 }
+		}
+		ex.retry = &retryPolicy{attempts: maxAttempts, backoff: backoff, classify: classifier}
+		return nil
+	}
+}
+
+// WithStatementTimeout sets the default timeout of the statements executed by the Executor.
+// Statements can override it using the atlas:stmt-timeout directive. For example:
+//
+//	-- atlas:stmt-timeout 5m
+//	CREATE INDEX CONCURRENTLY i ON t(c);
+func WithStatementTimeout(d time.Duration) ExecutorOption {
+	return func(ex *Executor) error {
+		if d < 0 {
+			return fmt.Errorf("sql/migrate: invalid statement timeout: %s", d)
+		}
+		ex.stmtTimeout = d
+		return nil
+	}
+}
+
 // Pending returns all pending (not fully applied) migration files in the migration directory.
 func (e *Executor) Pending(ctx context.Context) ([]File, error) {
 	// Don't operate with a broken migration directory.
@@ -801,23 +860,20 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 	if err != nil {
 		return fmt.Errorf("sql/migrate: scanning checksum from %q: %w", m.Name(), err)
 	}
-	stmts, err := e.fileStmts(m)
+	stmts, err := e.FileStmts(m)
 	if err != nil {
 		err = fmt.Errorf("sql/migrate: scanning statements from %q: %w", m.Name(), err)
 		e.log.Log(LogError{Error: err})
 		return err
 	}
-	// Create checksums for the statements.
-	var (
-		sums = make([]string, len(stmts))
-		h    = sha256.New()
-	)
-	for i, stmt := range stmts {
-		if _, err := h.Write([]byte(stmt.Text)); err != nil {
-			return err
-		}
-		sums[i] = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	timeout, err := fileTimeout(m, stmts)
+	if err != nil {
+		err = fmt.Errorf("sql/migrate: scanning timeouts from %q: %w", m.Name(), err)
+		e.log.Log(LogError{Error: err})
+		return err
 	}
+	// Create checksums for the statements.
+	sums := StmtHashes(stmts)
 	version := m.Version()
 	// If there already is a revision with this version in the database,
 	// and it is partially applied, continue where the last attempt was left off.
@@ -852,7 +908,7 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 		// If the file has been applied partially before, check if the
 		// applied statements have not changed.
 		for i := 0; i < r.Applied; i++ {
-			if i > len(sums) || sums[i] != strings.TrimPrefix(r.PartialHashes[i], "h1:") {
+			if i > len(sums) || strings.TrimPrefix(sums[i], "h1:") != strings.TrimPrefix(r.PartialHashes[i], "h1:") {
 				err = HistoryChangedError{m.Name(), i + 1}
 				e.log.Log(LogError{Error: err})
 				return err
@@ -860,7 +916,15 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 		}
 	}
 	e.log.Log(LogFile{m, r.Version, r.Description, r.Applied})
-	if err := e.fileChecks(ctx, m, r); err != nil {
+	// Statements are executed within the file timeout, if defined. The revision
+	// is written with the parent context, to record a timed out execution.
+	fctx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		fctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := e.fileChecks(fctx, m, r); err != nil {
 		e.log.Log(LogError{Error: err})
 		r.done()
 		r.Error = err.Error()
@@ -868,14 +932,17 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 	}
 	for _, stmt := range stmts[r.Applied:] {
 		e.log.Log(LogStmt{SQL: stmt.Text, Stmt: stmt})
-		if _, err = e.drv.ExecContext(ctx, stmt.Text); err != nil {
+		if err = e.execStmt(fctx, stmt); err != nil {
+			if timeout > 0 && errors.Is(fctx.Err(), context.DeadlineExceeded) && ctx.Err() == nil && !errors.As(err, new(*TimeoutError)) {
+				err = &TimeoutError{Directive: directiveTimeout, Timeout: timeout, Err: err}
+			}
 			e.log.Log(LogError{SQL: stmt.Text, Stmt: stmt, Error: err})
 			r.done()
 			r.ErrorStmt = stmt.Text
 			r.Error = err.Error()
 			return &StmtExecError{File: m, Stmt: stmt, Version: r.Version, Err: err}
 		}
-		r.PartialHashes = append(r.PartialHashes, "h1:"+sums[r.Applied])
+		r.PartialHashes = append(r.PartialHashes, sums[r.Applied])
 		r.Applied++
 		// In case retry attempts succeeded,
 		// clean up the error from the table.
@@ -894,6 +961,115 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 	return
 }
 
+// StmtHashes returns the partial hashes of the given statements, as they are recorded in the
+// revisions table when a file is applied statement by statement. The hash of each statement
+// is computed over all statements up to and including it.
+func StmtHashes(stmts []*Stmt) []string {
+	var (
+		h    = sha256.New()
+		sums = make([]string, len(stmts))
+	)
+	for i, stmt := range stmts {
+		h.Write([]byte(stmt.Text))
+		sums[i] = "h1:" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	return sums
+}
+
+// execStmt executes the given statement, and retries it according
+// to the retry policy of the executor, in case it was configured.
+func (e *Executor) execStmt(ctx context.Context, stmt *Stmt) error {
+	for n := 1; ; n++ {
+		err := e.execTimeout(ctx, stmt)
+		if err == nil || e.retry == nil || n >= e.retry.attempts || !e.retry.classify(err) {
+			return err
+		}
+		d := e.retry.backoff(n)
+		e.log.Log(LogRetry{SQL: stmt.Text, Stmt: stmt, Attempt: n, Delay: d, Error: err})
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(d):
+		}
+	}
+}
+
+// execTimeout executes the given statement within its timeout, if one was defined. Drivers
+// that implement the StmtTimeoutSetter interface enforce the timeout also on the database.
+func (e *Executor) execTimeout(ctx context.Context, stmt *Stmt) (err error) {
+	d, name := e.stmtTimeout, ""
+	if ds := stmt.Directive(directiveStmtTimeout); len(ds) > 0 {
+		// Validated by fileTimeout.
+		d, _ = parseTimeout(ds[0])
+		name = directiveStmtTimeout
+	}
+	if d == 0 {
+		_, err = e.drv.ExecContext(ctx, stmt.Text)
+		return err
+	}
+	if s, ok := e.drv.(StmtTimeoutSetter); ok {
+		restore, err := s.SetStmtTimeout(ctx, d)
+		if err != nil {
+			return fmt.Errorf("setting statement timeout: %w", err)
+		}
+		defer func() {
+			// Restoring the settings after a failure is best-effort, as
+			// the connection (or its transaction) might be unusable.
+			if err2 := restore(ctx); err2 != nil && err == nil {
+				err = fmt.Errorf("restoring statement timeout: %w", err2)
+			}
+		}()
+	}
+	sctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	start := time.Now()
+	// Native timeouts might be reported by the database before the context deadline.
+	if _, err = e.drv.ExecContext(sctx, stmt.Text); err != nil && ctx.Err() == nil && (sctx.Err() != nil || time.Since(start) >= d) {
+		err = &TimeoutError{Directive: name, Timeout: d, Err: err}
+	}
+	return err
+}
+
+// fileTimeout returns the timeout of the file, defined by the atlas:timeout
+// directive, and validates the atlas:stmt-timeout directives of its statements.
+func fileTimeout(f File, stmts []*Stmt) (time.Duration, error) {
+	for _, s := range stmts {
+		switch ds := s.Directive(directiveStmtTimeout); {
+		case len(ds) > 1:
+			return 0, fmt.Errorf("multiple atlas:stmt-timeout directives found for statement %q", s.Text)
+		case len(ds) == 1:
+			if _, err := parseTimeout(ds[0]); err != nil {
+				return 0, fmt.Errorf("atlas:stmt-timeout directive of statement %q: %w", s.Text, err)
+			}
+		}
+	}
+	l, ok := f.(interface{ Directive(string) []string })
+	if !ok {
+		return 0, nil
+	}
+	switch ds := l.Directive(directiveTimeout); len(ds) {
+	case 0:
+		return 0, nil
+	case 1:
+		d, err := parseTimeout(ds[0])
+		if err != nil {
+			return 0, fmt.Errorf("atlas:timeout directive: %w", err)
+		}
+		return d, nil
+	default:
+		return 0, fmt.Errorf("multiple atlas:timeout directives found: %q", ds)
+	}
+}
+
+// parseTimeout parses the argument of a timeout directive. e.g. "30s".
+func parseTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q, expect a positive duration. e.g. 30s", s)
+	}
+	return d, nil
+}
+
 func (e *Executor) writeRevision(ctx context.Context, r *Revision) error {
 	r.ExecutedAt = time.Now()
 	r.OperatorVersion = e.operator
@@ -1046,6 +1222,9 @@ func (e *Executor) exec(ctx context.Context, files []File) error {
 }
 
 type (
+	// replayCtxKey marks the context of a directory replay, see Replay.
+	replayCtxKey struct{}
+
 	replayConfig struct {
 		version string // to which version to replay (inclusive)
 	}
@@ -1066,6 +1245,7 @@ func (e *Executor) Replay(ctx context.Context, r StateReader, opts ...ReplayOpti
 	for _, opt := range opts {
 		opt(c)
 	}
+	ctx = context.WithValue(ctx, replayCtxKey{}, true)
 	// Clean up after ourselves.
 	restore, err := e.drv.Snapshot(ctx)
 	if err != nil {
@@ -1121,6 +1301,22 @@ type (
 		State  *schema.Realm // the state the dev-connection is in
 	}
 
+	// StmtTimeoutSetter wraps the SetStmtTimeout method. It is implemented by drivers
+	// that can enforce statement timeouts on the database, in addition to context deadlines.
+	StmtTimeoutSetter interface {
+		// SetStmtTimeout limits the execution and lock wait time of the statements executed next by the
+		// driver to d, and returns a function that restores the previous settings of the connection.
+		SetStmtTimeout(context.Context, time.Duration) (RestoreFunc, error)
+	}
+
+	// TimeoutError is the cause of a StmtExecError in case the statement did not
+	// complete within its timeout, or within the timeout of its migration file.
+	TimeoutError struct {
+		Directive string        // Directive that defined the timeout. Empty, if set by WithStatementTimeout.
+		Timeout   time.Duration // Timeout that was exceeded.
+		Err       error         // Underlying error during execution.
+	}
+
 	// StmtExecError is returned when the execution of a statement fails during migration.
 	StmtExecError struct {
 		File    File   // Migration file that failed.
@@ -1134,6 +1330,18 @@ func (e *StmtExecError) Unwrap() error {
 	return e.Err
 }
 
+func (e *TimeoutError) Error() string {
+	kind := "statement"
+	if e.Directive == directiveTimeout {
+		kind = "file"
+	}
+	return fmt.Sprintf("%s timeout of %s exceeded: %v", kind, e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
 func (e *NotCleanError) Error() string {
 	return "sql/migrate: connected database is not clean: " + e.Reason
 }
@@ -1217,6 +1425,17 @@ type (
 		Stmt *Stmt  // Scanned statement with extra information.
 	}
 
+	// LogRetry is sent if a statement failed with a transient
+	// error, and is about to be retried. See WithRetryPolicy.
+	LogRetry struct {
+		SQL     string        // SQL statement.
+		Stmt    *Stmt         // Scanned statement with extra information.
+		File    File          // Set if the file is retried as a whole, e.g., in a transaction.
+		Attempt int           // Number of the failed attempt, starting at 1.
+		Delay   time.Duration // Delay before the next attempt.
+		Error   error         // Error of the failed attempt.
+	}
+
 	// LogDone is sent if the execution is done.
 	LogDone struct{}
 
@@ -1254,6 +1473,7 @@ type (
 func (LogExecution) logEntry()  {}
 func (LogFile) logEntry()       {}
 func (LogStmt) logEntry()       {}
+func (LogRetry) logEntry()      {}
 func (LogCheck) logEntry()      {}
 func (LogChecks) logEntry()     {}
 func (LogChecksDone) logEntry() {}
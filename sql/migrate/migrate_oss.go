@@ -8,6 +8,8 @@ package migrate
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 )
 
@@ -21,13 +23,118 @@ func (f *LocalFile) CheckpointTag() (string, error) {
 	return f.checkpointTag()
 }
 
-// fileStmts returns the statements defined in the given file.
-func (e *Executor) fileStmts(f File) ([]*Stmt, error) {
-	return FileStmtDecls(e.drv, f)
+// FileStmts returns the statements of the given file that are executed and recorded
+// in its revision, excluding the assertions that are executed before them.
+func (e *Executor) FileStmts(f File) ([]*Stmt, error) {
+	stmts, err := FileStmtDecls(e.drv, f)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := fileAsserts(stmts)
+	if err != nil {
+		return nil, err
+	}
+	return stmts[len(checks):], nil
 }
 
-func (e *Executor) fileChecks(context.Context, File, *Revision) error {
-	return nil // unimplemented
+// fileChecks executes the assertions defined at the top of the file, and
+// returns an AssertError in case one of them did not evaluate to true. For
+// example:
+//
+//	-- atlas:assert users without an email
+//	SELECT count(*) = 0 FROM users WHERE email IS NULL;
+//
+//	ALTER TABLE users MODIFY email varchar(255) NOT NULL;
+//
+// Assertions guard the first execution of the file. Hence, they are not
+// executed when a partially applied file is resumed, or when the migration
+// directory is replayed on a dev database.
+func (e *Executor) fileChecks(ctx context.Context, f File, r *Revision) error {
+	if r.Applied > 0 || ctx.Value(replayCtxKey{}) != nil {
+		return nil
+	}
+	stmts, err := FileStmtDecls(e.drv, f)
+	if err != nil {
+		return err
+	}
+	checks, err := fileAsserts(stmts)
+	if err != nil || len(checks) == 0 {
+		return err
+	}
+	texts := make([]string, len(checks))
+	for i, c := range checks {
+		texts[i] = c.Text
+	}
+	e.log.Log(LogChecks{Name: f.Name(), Stmts: texts})
+	for _, c := range checks {
+		err := e.assert(ctx, f, c)
+		e.log.Log(LogCheck{Stmt: c.Text, Decl: c, Error: err})
+		if err != nil {
+			e.log.Log(LogChecksDone{Error: err})
+			return err
+		}
+	}
+	e.log.Log(LogChecksDone{})
+	return nil
+}
+
+// assert executes the given assertion, and expects it to return a single true value.
+func (e *Executor) assert(ctx context.Context, f File, s *Stmt) error {
+	rows, err := e.drv.QueryContext(ctx, s.Text)
+	if err != nil {
+		return &AssertError{File: f, Stmt: s, Err: err}
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return &AssertError{File: f, Stmt: s, Err: err}
+		}
+		return &AssertError{File: f, Stmt: s, Err: errors.New("no rows were returned")}
+	}
+	var ok sql.NullBool
+	if err := rows.Scan(&ok); err != nil {
+		return &AssertError{File: f, Stmt: s, Err: fmt.Errorf("scanning boolean result: %w", err)}
+	}
+	if !ok.Bool {
+		return &AssertError{File: f, Stmt: s}
+	}
+	return rows.Close()
+}
+
+// AssertError is returned when an assertion of a migration file fails.
+type AssertError struct {
+	File File  // Migration file that failed.
+	Stmt *Stmt // Assertion that failed.
+	Err  error // Underlying error, if the assertion could not be evaluated.
+}
+
+func (e *AssertError) Error() string {
+	msg := "assertion failed"
+	if ds := e.Stmt.Directive(directiveAssert); len(ds) > 0 && ds[0] != "" {
+		msg += ": " + ds[0]
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return fmt.Sprintf("sql/migrate: %s in file %q", msg, e.File.Name())
+}
+
+func (e *AssertError) Unwrap() error { return e.Err }
+
+// fileAsserts returns the leading statements of the file that are marked with
+// the atlas:assert directive. Assertions that follow other statements are not
+// allowed, as they would be executed as part of the migration.
+func fileAsserts(stmts []*Stmt) ([]*Stmt, error) {
+	n := 0
+	for n < len(stmts) && len(stmts[n].Directive(directiveAssert)) > 0 {
+		n++
+	}
+	for _, s := range stmts[n:] {
+		if len(s.Directive(directiveAssert)) > 0 {
+			return nil, fmt.Errorf("atlas:assert directive of statement %q must precede all other statements", s.Text)
+		}
+	}
+	return stmts[:n], nil
 }
 
 // ValidateDir before operating on it.
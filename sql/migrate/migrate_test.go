@@ -11,6 +11,7 @@ import (
 	"errors"
 	"io/fs"
 	"path/filepath"
+	"strings"
 	"testing"
 	"text/template"
 	"time"
@@ -616,6 +617,167 @@ func TestExecutor_Baseline(t *testing.T) {
 	require.Equal(t, migrate.RevisionTypeBaseline, rrw[0].Type)
 }
 
+func TestExecutor_Assert(t *testing.T) {
+	var (
+		drv = &mockDriver{}
+		rrw = &mockRevisionReadWriter{}
+		dir = &migrate.MemDir{}
+	)
+	require.NoError(t, dir.WriteFile("1.sql", []byte("-- atlas:assert\nSELECT true;\n\nCREATE TABLE t(c int);\n")))
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	ex, err := migrate.NewExecutor(drv, dir, rrw)
+	require.NoError(t, err)
+
+	// Assertions are not executed on replay.
+	_, err = ex.Replay(context.Background(), migrate.RealmConn(drv, nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"CREATE TABLE t(c int);"}, drv.executed)
+	require.Equal(t, 1, (*rrw)[0].Total)
+
+	// Assertions must precede all other statements.
+	drv.executed, *rrw = nil, nil
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t(c int);\n-- atlas:assert\nSELECT true;\n")))
+	sum, err = dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	err = ex.ExecuteN(context.Background(), 0)
+	require.EqualError(t, err, `sql/migrate: scanning statements from "1.sql": atlas:assert directive of statement "SELECT true;" must precede all other statements`)
+	require.Empty(t, drv.executed)
+}
+
+func TestExecutor_Retry(t *testing.T) {
+	var (
+		drv = &mockDriver{}
+		rrw = &mockRevisionReadWriter{}
+		log = &mockLogger{}
+		dir = &migrate.MemDir{}
+	)
+	_, err := migrate.NewExecutor(drv, dir, rrw, migrate.WithRetryPolicy(0, nil, func(error) bool { return true }))
+	require.EqualError(t, err, "sql/migrate: invalid number of retry attempts: 0")
+	_, err = migrate.NewExecutor(drv, dir, rrw, migrate.WithRetryPolicy(1, nil, nil))
+	require.EqualError(t, err, "sql/migrate: missing retry classifier")
+
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t1(c int);\nCREATE TABLE t2(c int);\n")))
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	transient := errors.New("deadlock")
+	ex, err := migrate.NewExecutor(drv, dir, rrw, migrate.WithLogger(log), migrate.WithRetryPolicy(2, nil, func(err error) bool {
+		return errors.Is(err, transient)
+	}))
+	require.NoError(t, err)
+
+	// Transient errors are retried.
+	drv.failOn(2, transient)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);"}, drv.executed)
+	var retries []migrate.LogRetry
+	for _, e := range *log {
+		if r, ok := e.(migrate.LogRetry); ok {
+			retries = append(retries, r)
+		}
+	}
+	require.Len(t, retries, 1)
+	require.Equal(t, "CREATE TABLE t2(c int);", retries[0].SQL)
+	require.Equal(t, 1, retries[0].Attempt)
+	require.ErrorIs(t, retries[0].Error, transient)
+
+	// Other errors are not retried.
+	drv.executed, *rrw, *log = nil, nil, nil
+	drv.failOn(1, errors.New("syntax error"))
+	require.EqualError(t, ex.ExecuteN(context.Background(), 0), `sql/migrate: executing statement "CREATE TABLE t1(c int);" from version "1": syntax error`)
+	require.Empty(t, drv.executed)
+}
+
+func TestExecutor_Timeout(t *testing.T) {
+	var (
+		drv = &timeoutDriver{mockDriver: &mockDriver{}}
+		rrw = &mockRevisionReadWriter{}
+		dir = &migrate.MemDir{}
+	)
+	_, err := migrate.NewExecutor(drv, dir, rrw, migrate.WithStatementTimeout(-time.Second))
+	require.EqualError(t, err, "sql/migrate: invalid statement timeout: -1s")
+	write := func(content string) {
+		require.NoError(t, dir.WriteFile("1.sql", []byte(content)))
+		sum, err := dir.Checksum()
+		require.NoError(t, err)
+		require.NoError(t, migrate.WriteSumFile(dir, sum))
+		drv.executed, drv.timeouts, *rrw = nil, nil, nil
+	}
+
+	// Statement timeout.
+	write("CREATE TABLE t1(c int);\n-- atlas:stmt-timeout 10ms\nSELECT pg_sleep(1);\n")
+	ex, err := migrate.NewExecutor(drv, dir, rrw)
+	require.NoError(t, err)
+	err = ex.ExecuteN(context.Background(), 0)
+	var terr *migrate.TimeoutError
+	require.ErrorAs(t, err, &terr)
+	require.Equal(t, "stmt-timeout", terr.Directive)
+	require.Equal(t, 10*time.Millisecond, terr.Timeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualError(t, err, `sql/migrate: executing statement "SELECT pg_sleep(1);" from version "1": statement timeout of 10ms exceeded: context deadline exceeded`)
+	require.Equal(t, []string{"CREATE TABLE t1(c int);"}, drv.executed)
+	require.Equal(t, []time.Duration{10 * time.Millisecond}, drv.timeouts, "native timeout is set only for the statement")
+	require.Equal(t, 1, drv.restored)
+
+	// Default statement timeout.
+	write("CREATE TABLE t1(c int);\nSELECT pg_sleep(1);\n")
+	ex, err = migrate.NewExecutor(drv, dir, rrw, migrate.WithStatementTimeout(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, ex.ExecuteN(context.Background(), 0))
+	require.Equal(t, []time.Duration{time.Minute, time.Minute}, drv.timeouts)
+
+	// File timeout.
+	write("-- atlas:timeout 10ms\n\nCREATE TABLE t1(c int);\nSELECT pg_sleep(1);\n")
+	ex, err = migrate.NewExecutor(drv, dir, rrw)
+	require.NoError(t, err)
+	err = ex.ExecuteN(context.Background(), 0)
+	require.ErrorAs(t, err, &terr)
+	require.Equal(t, "timeout", terr.Directive)
+	require.EqualError(t, err, `sql/migrate: executing statement "SELECT pg_sleep(1);" from version "1": file timeout of 10ms exceeded: context deadline exceeded`)
+	require.Empty(t, drv.timeouts)
+	require.Len(t, *rrw, 1)
+	require.Equal(t, 1, (*rrw)[0].Applied, "revision is recorded after the timeout")
+
+	// Invalid directives.
+	write("-- atlas:stmt-timeout 1x\nCREATE TABLE t1(c int);\n")
+	err = ex.ExecuteN(context.Background(), 0)
+	require.EqualError(t, err, `sql/migrate: scanning timeouts from "1.sql": atlas:stmt-timeout directive of statement "CREATE TABLE t1(c int);": invalid timeout "1x", expect a positive duration. e.g. 30s`)
+	write("-- atlas:timeout 0s\n\nCREATE TABLE t1(c int);\n")
+	err = ex.ExecuteN(context.Background(), 0)
+	require.EqualError(t, err, `sql/migrate: scanning timeouts from "1.sql": atlas:timeout directive: invalid timeout "0s", expect a positive duration. e.g. 30s`)
+	require.Empty(t, drv.executed)
+}
+
+// timeoutDriver blocks on sleep statements until their context is done,
+// and records the timeouts that were set by the executor.
+type timeoutDriver struct {
+	*mockDriver
+	timeouts []time.Duration
+	restored int
+}
+
+func (d *timeoutDriver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if strings.Contains(query, "pg_sleep") {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return d.mockDriver.ExecContext(ctx, query, args...)
+}
+
+func (d *timeoutDriver) SetStmtTimeout(_ context.Context, timeout time.Duration) (migrate.RestoreFunc, error) {
+	d.timeouts = append(d.timeouts, timeout)
+	return func(context.Context) error {
+		d.restored++
+		return nil
+	}, nil
+}
+
 type (
 	mockDriver struct {
 		migrate.Driver
@@ -10,7 +10,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -294,6 +297,47 @@ func (*Driver) ScanStmts(input string) ([]*migrate.Stmt, error) {
 	}).Scan(input)
 }
 
+// SetStmtTimeout implements migrate.StmtTimeoutSetter. The timeout is enforced using the
+// max_execution_time (max_statement_time on MariaDB) and lock_wait_timeout session variables.
+// Note, MySQL applies max_execution_time only to read-only SELECT statements.
+func (d *Driver) SetStmtTimeout(ctx context.Context, timeout time.Duration) (migrate.RestoreFunc, error) {
+	if !sqlx.IsSingleConn(d.ExecQuerier) {
+		return func(context.Context) error { return nil }, nil
+	}
+	execVar := "max_execution_time"
+	execVal := strconv.FormatInt(max(timeout.Milliseconds(), 1), 10)
+	if d.Maria() {
+		execVar, execVal = "max_statement_time", strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)
+	}
+	rows, err := d.QueryContext(ctx, fmt.Sprintf("SELECT @@SESSION.%s, @@SESSION.lock_wait_timeout", execVar))
+	if err != nil {
+		return nil, err
+	}
+	var prevExec, prevLock string
+	if err := sqlx.ScanOne(rows, &prevExec, &prevLock); err != nil {
+		return nil, err
+	}
+	// lock_wait_timeout is defined in seconds, and must be at least 1.
+	lock := strconv.FormatInt(int64(math.Ceil(timeout.Seconds())), 10)
+	if _, err := d.ExecContext(ctx, fmt.Sprintf("SET SESSION %s = %s, lock_wait_timeout = %s", execVar, execVal, lock)); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		_, err := d.ExecContext(ctx, fmt.Sprintf("SET SESSION %s = %s, lock_wait_timeout = %s", execVar, prevExec, prevLock))
+		return err
+	}, nil
+}
+
+// IsRetryable reports if the given statement execution error is transient, and the statement
+// can be retried: deadlocks (1213) and lock wait timeouts (1205). It is intended to be used
+// with migrate.WithRetryPolicy.
+func IsRetryable(err error) bool {
+	return err != nil && reRetryable.MatchString(err.Error())
+}
+
+// Errors are formatted as "Error <number> (<sqlstate>): <message>" by the MySQL driver.
+var reRetryable = regexp.MustCompile(`\bError (?:1213|1205)\b`)
+
 func acquire(ctx context.Context, conn schema.ExecQuerier, name string, timeout time.Duration) error {
 	rows, err := conn.QueryContext(ctx, "SELECT GET_LOCK(?, ?)", name, int(timeout.Seconds()))
 	if err != nil {
@@ -9,6 +9,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/url"
 	"testing"
 	"time"
@@ -57,6 +58,45 @@ func TestParser_ParseURL(t *testing.T) {
 	})
 }
 
+func TestDriver_SetStmtTimeout(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	m.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	m.ExpectQuery(sqltest.Escape("SELECT @@SESSION.max_execution_time, @@SESSION.lock_wait_timeout")).
+		WillReturnRows(sqlmock.NewRows([]string{"max_execution_time", "lock_wait_timeout"}).AddRow("0", "31536000"))
+	m.ExpectExec(sqltest.Escape("SET SESSION max_execution_time = 1500, lock_wait_timeout = 2")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET SESSION max_execution_time = 0, lock_wait_timeout = 31536000")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d := &Driver{conn: &conn{ExecQuerier: tx, V: "8.0.31"}}
+	restore, err := d.SetStmtTimeout(ctx, 1500*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, restore(ctx))
+
+	m.ExpectQuery(sqltest.Escape("SELECT @@SESSION.max_statement_time, @@SESSION.lock_wait_timeout")).
+		WillReturnRows(sqlmock.NewRows([]string{"max_statement_time", "lock_wait_timeout"}).AddRow("0.000000", "86400"))
+	m.ExpectExec(sqltest.Escape("SET SESSION max_statement_time = 1.5, lock_wait_timeout = 2")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET SESSION max_statement_time = 0.000000, lock_wait_timeout = 86400")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d = &Driver{conn: &conn{ExecQuerier: tx, V: "10.11.2-MariaDB"}}
+	restore, err = d.SetStmtTimeout(ctx, 1500*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, restore(ctx))
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.True(t, IsRetryable(errors.New("Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction")))
+	require.True(t, IsRetryable(errors.New("Error 1205 (HY000): Lock wait timeout exceeded; try restarting transaction")))
+	require.False(t, IsRetryable(errors.New("Error 1050 (42S01): Table 't' already exists")))
+	require.False(t, IsRetryable(nil))
+}
+
 func TestDriver_LockAcquired(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
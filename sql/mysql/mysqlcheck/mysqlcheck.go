@@ -16,10 +16,17 @@ import (
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/brandedcheck"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
+	"ariga.io/atlas/sql/sqlcheck/custom"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
+	"ariga.io/atlas/sql/sqlcheck/dataloss"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
+	"ariga.io/atlas/sql/sqlcheck/dmlsafety"
 	"ariga.io/atlas/sql/sqlcheck/incompatible"
+	"ariga.io/atlas/sql/sqlcheck/naming"
+	"ariga.io/atlas/sql/sqlcheck/structural"
+	"ariga.io/atlas/sql/sqlcheck/txsafety"
 )
 
 var (
@@ -157,6 +164,62 @@ func columnFilledAfter(pass *datadepend.ColumnPass, matchValue string) bool {
 	return filled
 }
 
+// stringSize returns the maximum number of characters a string column can hold.
+// The capacity of TEXT types is defined in bytes, and used here as an upper bound.
+func stringSize(t *schema.StringType) int64 {
+	switch t.T {
+	case mysql.TypeTinyText:
+		return 1<<8 - 1
+	case mysql.TypeText:
+		return 1<<16 - 1
+	case mysql.TypeMediumText:
+		return 1<<24 - 1
+	case mysql.TypeLongText:
+		return 1<<32 - 1
+	default:
+		return int64(t.Size)
+	}
+}
+
+// integerSize returns the storage size in bytes of an integer type.
+func integerSize(t *schema.IntegerType) int {
+	switch t.T {
+	case mysql.TypeTinyInt:
+		return 1
+	case mysql.TypeSmallInt:
+		return 2
+	case mysql.TypeMediumInt:
+		return 3
+	case mysql.TypeInt, "integer":
+		return 4
+	case mysql.TypeBigInt:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// decimalSize returns the effective precision and scale of a decimal type.
+func decimalSize(t *schema.DecimalType) (int, int) {
+	if t.Precision == 0 {
+		// The default precision of DECIMAL is 10.
+		return 10, t.Scale
+	}
+	return t.Precision, t.Scale
+}
+
+// timePrecision returns the fractional seconds precision of a time type.
+func timePrecision(t *schema.TimeType) int {
+	switch {
+	case t.T == mysql.TypeDate || t.T == mysql.TypeYear:
+		return -1
+	case t.Precision != nil:
+		return *t.Precision
+	default:
+		return 0
+	}
+}
+
 // inlineRefs is an analyzer function that detects column definitions with the REFERENCES
 // clause and suggest replacing them with an explicit foreign-key definition.
 func inlineRefs(_ context.Context, p *sqlcheck.Pass) error {
@@ -203,6 +266,28 @@ func inlineRefs(_ context.Context, p *sqlcheck.Pass) error {
 	return nil
 }
 
+// tableStats reads the estimated number of rows and the size of the tables in
+// the database, as reported by the storage engine in INFORMATION_SCHEMA.
+func tableStats(ctx context.Context, conn schema.ExecQuerier) (map[string]sqlcheck.TableStats, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, COALESCE(`TABLE_ROWS`, 0), COALESCE(`DATA_LENGTH`, 0) + COALESCE(`INDEX_LENGTH`, 0) FROM `INFORMATION_SCHEMA`.`TABLES` WHERE `TABLE_TYPE` = 'BASE TABLE' AND `TABLE_SCHEMA` NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := make(map[string]sqlcheck.TableStats)
+	for rows.Next() {
+		var (
+			s, t    string
+			n, size int64
+		)
+		if err := rows.Scan(&s, &t, &n, &size); err != nil {
+			return nil, err
+		}
+		stats[s+"."+t] = sqlcheck.TableStats{Rows: n, Bytes: size}
+	}
+	return stats, rows.Err()
+}
+
 func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	ds, err := destructive.New(r)
 	if err != nil {
@@ -222,5 +307,52 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return []sqlcheck.Analyzer{ds, dd, cd, bc, sqlcheck.AnalyzerFunc(inlineRefs)}, nil
+	dl, err := dataloss.New(r, dataloss.Handler{
+		StringSize:    stringSize,
+		IntegerSize:   integerSize,
+		DecimalSize:   decimalSize,
+		TimePrecision: timePrecision,
+	})
+	if err != nil {
+		return nil, err
+	}
+	nm, err := naming.New(r)
+	if err != nil {
+		return nil, err
+	}
+	st, err := structural.New(r)
+	if err != nil {
+		return nil, err
+	}
+	od, err := NewOnlineDDL(r)
+	if err != nil {
+		return nil, err
+	}
+	// DDL statements in MySQL cause an implicit commit.
+	// See: https://dev.mysql.com/doc/refman/8.0/en/implicit-commit.html.
+	tx, err := txsafety.New(r, txsafety.Handler{
+		ImplicitCommit: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dm, err := dmlsafety.New(r, dmlsafety.MySQL)
+	if err != nil {
+		return nil, err
+	}
+	br, err := brandedcheck.New(r)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := custom.New(r)
+	if err != nil {
+		return nil, err
+	}
+	as := []sqlcheck.Analyzer{ds, dd, cd, bc, dl, nm, st, tx, dm, br, cr, sqlcheck.AnalyzerFunc(inlineRefs)}
+	// Online DDL checks are opt-in, as they report
+	// changes that are safe to run on small tables.
+	if _, ok := r.Resource(od.Name()); ok {
+		as = append(as, od)
+	}
+	return as, nil
 }
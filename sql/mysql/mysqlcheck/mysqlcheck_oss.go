@@ -13,4 +13,5 @@ import (
 
 func init() {
 	sqlcheck.Register(mysql.DriverName, analyzers)
+	sqlcheck.RegisterStats(mysql.DriverName, tableStats)
 }
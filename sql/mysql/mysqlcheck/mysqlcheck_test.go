@@ -6,12 +6,15 @@ package mysqlcheck_test
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/internal/sqltest"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/mysql"
-	_ "ariga.io/atlas/sql/mysql/mysqlcheck"
+	"ariga.io/atlas/sql/mysql/mysqlcheck"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlclient"
@@ -230,6 +233,252 @@ func TestDataDepend_Maria_ImplicitUpdate(t *testing.T) {
 
 }
 
+func TestOnlineDDL_MySQL(t *testing.T) {
+	users := schema.NewTable("users").
+		SetSchema(schema.New("test")).
+		AddColumns(
+			schema.NewIntColumn("id", mysql.TypeBigInt),
+			schema.NewStringColumn("name", mysql.TypeVarchar, schema.StringSize(64)),
+			schema.NewStringColumn("bio", mysql.TypeText),
+		)
+	var (
+		report *sqlcheck.Report
+		pass   = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{
+				Name:   "mysql",
+				Driver: devDriver(t, "8.0.19"),
+			},
+			File: &sqlcheck.File{
+				File: migrate.NewLocalFile("1.sql", []byte("ALTER TABLE users MODIFY id int NOT NULL;\nALTER TABLE users MODIFY name varchar(63) NOT NULL;\nCREATE FULLTEXT INDEX bio ON users (bio);\n")),
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{Pos: 0, Text: "ALTER TABLE users MODIFY id int NOT NULL;"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.ModifyColumn{
+										From:   schema.NewIntColumn("id", mysql.TypeBigInt),
+										To:     schema.NewIntColumn("id", mysql.TypeInt),
+										Change: schema.ChangeType,
+									},
+								},
+							},
+						},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 42, Text: "ALTER TABLE users MODIFY name varchar(63) NOT NULL;"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.ModifyColumn{
+										From:   schema.NewStringColumn("name", mysql.TypeVarchar, schema.StringSize(32)),
+										To:     schema.NewStringColumn("name", mysql.TypeVarchar, schema.StringSize(63)),
+										Change: schema.ChangeType,
+									},
+								},
+							},
+						},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 94, Text: "CREATE FULLTEXT INDEX bio ON users (bio);"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.AddIndex{
+										I: schema.NewIndex("bio").
+											AddColumns(users.Columns[2]).
+											AddAttrs(&mysql.IndexType{T: mysql.IndexTypeFullText}),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	az, err := mysqlcheck.NewOnlineDDL(nil)
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, "table locking changes detected", report.Text)
+	require.Len(t, report.Diagnostics, 2)
+	require.Equal(t, "MY103", report.Diagnostics[0].Code)
+	require.Equal(t, `Changing the type of column "id" on table "users" requires ALGORITHM=COPY, which rebuilds the table and blocks concurrent writes`, report.Diagnostics[0].Text)
+	require.Equal(t, "MY104", report.Diagnostics[1].Code)
+	require.Equal(t, `Adding index "bio" on table "users" runs in place, but blocks concurrent writes (LOCK=SHARED)`, report.Diagnostics[1].Text)
+
+	// Pinned algorithms are not reported.
+	pass.File.Changes[0].Stmt.Text = "ALTER TABLE users MODIFY id int NOT NULL, ALGORITHM=INPLACE;"
+	pass.File.Changes = pass.File.Changes[:2]
+	report = nil
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Nil(t, report)
+
+	// Suggest pinning the algorithm for online changes.
+	az, err = mysqlcheck.NewOnlineDDL(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "online_ddl",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("suggest", true),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, az.Suggest)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "MY105", report.Diagnostics[0].Code)
+	require.Equal(t, `Extending the size of column "name" on table "users" can run with ALGORITHM=INPLACE, LOCK=NONE`, report.Diagnostics[0].Text)
+	require.Equal(t, &sqlcheck.TextEdit{Line: 2, End: 2, NewText: "ALTER TABLE users MODIFY name varchar(63) NOT NULL, ALGORITHM=INPLACE, LOCK=NONE;"}, report.Diagnostics[0].SuggestedFixes[0].TextEdit)
+
+	// Text that shares the line with the statement is kept.
+	pass.File.File = migrate.NewLocalFile("1.sql", []byte("ALTER TABLE users MODIFY id int NOT NULL, ALGORITHM=INPLACE;\nSELECT 1; ALTER TABLE users MODIFY name varchar(63) NOT NULL; -- extend name\n"))
+	pass.File.Changes[1].Stmt.Pos = 71
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, &sqlcheck.TextEdit{Line: 2, End: 2, NewText: "SELECT 1; ALTER TABLE users MODIFY name varchar(63) NOT NULL, ALGORITHM=INPLACE, LOCK=NONE; -- extend name"}, report.Diagnostics[0].SuggestedFixes[0].TextEdit)
+}
+
+func TestOnlineDDL_Versions(t *testing.T) {
+	users := schema.NewTable("users").
+		SetSchema(schema.New("test")).
+		AddColumns(schema.NewIntColumn("id", mysql.TypeBigInt))
+	for _, tt := range []struct {
+		version, stmt, want string
+	}{
+		{version: "5.7.0", stmt: "ALTER TABLE users ADD COLUMN c int;", want: "ALGORITHM=INPLACE, LOCK=NONE"},
+		{version: "8.0.19", stmt: "ALTER TABLE users ADD COLUMN c int;", want: "ALGORITHM=INSTANT"},
+		{version: "8.0.19", stmt: "ALTER TABLE users ADD COLUMN c int FIRST;", want: "ALGORITHM=INPLACE, LOCK=NONE"},
+		{version: "8.0.30", stmt: "ALTER TABLE users ADD COLUMN c int AFTER `id`;", want: "ALGORITHM=INSTANT"},
+		{version: "10.2.0-MariaDB", stmt: "ALTER TABLE users ADD COLUMN c int;", want: "ALGORITHM=INPLACE, LOCK=NONE"},
+		{version: "10.3.7-MariaDB", stmt: "ALTER TABLE users ADD COLUMN c int;", want: "ALGORITHM=INSTANT"},
+	} {
+		var (
+			report *sqlcheck.Report
+			pass   = &sqlcheck.Pass{
+				Dev: &sqlclient.Client{
+					Name:   "mysql",
+					Driver: devDriver(t, tt.version),
+				},
+				File: &sqlcheck.File{
+					File: migrate.NewLocalFile("1.sql", []byte(tt.stmt)),
+					Changes: []*sqlcheck.Change{
+						{
+							Stmt: &migrate.Stmt{Text: tt.stmt},
+							Changes: schema.Changes{
+								&schema.ModifyTable{
+									T: users,
+									Changes: schema.Changes{
+										&schema.AddColumn{C: schema.NewIntColumn("c", mysql.TypeInt)},
+									},
+								},
+							},
+						},
+					},
+				},
+				Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+					report = &r
+				}),
+			}
+		)
+		az := &mysqlcheck.OnlineDDL{Suggest: true}
+		require.NoError(t, az.Analyze(context.Background(), pass))
+		require.Len(t, report.Diagnostics, 1, tt.version)
+		require.Equal(t, `Adding column "c" on table "users" can run with `+tt.want, report.Diagnostics[0].Text, tt.version)
+	}
+}
+
+func TestDataLoss_MySQL(t *testing.T) {
+	db, mk, err := sqlmock.New()
+	require.NoError(t, err)
+	mk.ExpectQuery("SELECT @@version, @@collation_server, @@character_set_server, @@lower_case_table_name").
+		WillReturnRows(sqltest.Rows(`
++-----------------+--------------------+------------------------+--------------------------+ 
+| @@version       | @@collation_server | @@character_set_server | @@lower_case_table_names | 
++-----------------+--------------------+------------------------+--------------------------+ 
+| 8.0.19          | utf8_general_ci    | utf8                   | 0                        | 
++-----------------+--------------------+------------------------+--------------------------+ 
+`))
+	drv, err := mysql.Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT COUNT(*) FROM `test`.`users` WHERE CHAR_LENGTH(`name`) > 64")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mk.ExpectQuery(sqltest.Escape("SELECT COUNT(*) FROM `test`.`users` WHERE `id` < -2147483648 OR `id` > 2147483647")).
+		WillReturnError(errors.New("table does not exist"))
+	var (
+		reports []sqlcheck.Report
+		users   = schema.NewTable("users").SetSchema(schema.New("test"))
+		pass    = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{
+				Name:   "mysql",
+				Driver: drv,
+			},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{Text: "ALTER TABLE users MODIFY name varchar(64), MODIFY id int, MODIFY bio varchar(255)"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.ModifyColumn{
+										From:   schema.NewStringColumn("name", mysql.TypeVarchar, schema.StringSize(255)),
+										To:     schema.NewStringColumn("name", mysql.TypeVarchar, schema.StringSize(64)),
+										Change: schema.ChangeType,
+									},
+									&schema.ModifyColumn{
+										From:   schema.NewIntColumn("id", mysql.TypeBigInt),
+										To:     schema.NewIntColumn("id", mysql.TypeInt),
+										Change: schema.ChangeType,
+									},
+									&schema.ModifyColumn{
+										From:   schema.NewStringColumn("bio", mysql.TypeText),
+										To:     schema.NewStringColumn("bio", mysql.TypeVarchar, schema.StringSize(255)),
+										Change: schema.ChangeType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				reports = append(reports, r)
+			}),
+		}
+	)
+	azs, err := sqlcheck.AnalyzerFor(mysql.DriverName, &schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "data_loss",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("count_rows", true),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	idx := slices.IndexFunc(reports, func(r sqlcheck.Report) bool {
+		return r.Text == "data loss changes detected"
+	})
+	require.NotEqual(t, -1, idx)
+	diags := reports[idx].Diagnostics
+	require.Len(t, diags, 3)
+	require.Equal(t, `Reducing the length of column "name" on table "users" from varchar(255) to varchar(64) might truncate existing values (3 existing rows do not fit the new type)`, diags[0].Text)
+	require.Equal(t, `Reducing the range of column "id" on table "users" from bigint to int might truncate existing values`, diags[1].Text)
+	require.Equal(t, `Reducing the length of column "bio" on table "users" from text to varchar(255) might truncate existing values`, diags[2].Text)
+}
+
 type testFile struct {
 	name string
 	migrate.File
@@ -0,0 +1,484 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysqlcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// OnlineDDL checks how InnoDB executes ALTER TABLE statements, and reports
+// statements that copy the table or block concurrent writes while running.
+// The analyzer is enabled only if it is configured in the lint block:
+//
+//	lint {
+//	  online_ddl {
+//	    suggest = true
+//	  }
+//	}
+//
+// See: https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html
+type OnlineDDL struct {
+	sqlcheck.Options
+	// Suggest reports statements that can run online, but do not pin the
+	// ALGORITHM and LOCK clauses. Pinning them ensures the server fails the
+	// statement instead of falling back silently to a table copy.
+	Suggest bool
+}
+
+// NewOnlineDDL creates a new online DDL Analyzer with the given options.
+func NewOnlineDDL(r *schemahcl.Resource) (*OnlineDDL, error) {
+	az := &OnlineDDL{}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/mysql: parsing online_ddl check options: %w", err)
+		}
+		if a, ok := az.Remain().Attr("suggest"); ok {
+			b, err := a.Bool()
+			if err != nil {
+				return nil, fmt.Errorf("sql/mysql: parsing online_ddl suggest option: %w", err)
+			}
+			az.Suggest = b
+		}
+	}
+	return az, nil
+}
+
+var (
+	// codeTableCopy is a MySQL specific code for reporting ALTER statements that copy the table.
+	codeTableCopy = sqlcheck.Code("MY103")
+	// codeBlockWrites is a MySQL specific code for reporting in-place ALTER statements that block writes.
+	codeBlockWrites = sqlcheck.Code("MY104")
+	// codeOnlineDDL is a MySQL specific code for suggesting pinning the online DDL algorithm.
+	codeOnlineDDL = sqlcheck.Code("MY105")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*OnlineDDL) Name() string {
+	return "online_ddl"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *OnlineDDL) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	drv, ok := p.Dev.Driver.(*mysql.Driver)
+	// TiDB executes all schema changes online.
+	if !ok || drv.TiDB() {
+		return nil
+	}
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			var (
+				t    *schema.Table
+				cost ddlCost
+			)
+			switch c := c.(type) {
+			case *schema.RenameTable:
+				if t = c.From; p.File.TableSpan(t)&sqlcheck.SpanAdded == 0 {
+					cost = cost.add(instantIf(drv.GTE("8.0.12"), inplaceOp), fmt.Sprintf("renaming table %q", t.Name))
+				}
+			case *schema.ModifyTable:
+				if t = c.T; p.File.TableSpan(t)&sqlcheck.SpanAdded == 0 {
+					cost = tableCost(drv, t, sc.Stmt.Text, c.Changes)
+				}
+			}
+			if t == nil || len(cost.reasons) == 0 || !innoDB(t) {
+				continue
+			}
+			if d, ok := a.diagnostic(p, sc.Stmt, t, cost); ok {
+				diags = append(diags, d)
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "table locking changes detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+var (
+	reAlterTable = regexp.MustCompile(`(?i)^\s*ALTER\s+TABLE\s`)
+	reAlgorithm  = regexp.MustCompile(`(?i)\bALGORITHM\s*=?\s*(DEFAULT|INSTANT|INPLACE|COPY|NOCOPY)\b`)
+	reLock       = regexp.MustCompile(`(?i)\bLOCK\s*=?\s*(DEFAULT|NONE|SHARED|EXCLUSIVE)\b`)
+	// reColumnPos is a best-effort detection of FIRST/AFTER positional clauses.
+	reColumnPos = regexp.MustCompile("(?i)\\s(FIRST|AFTER\\s+(`[^`]+`|\\w+))\\s*(,|;|$)")
+)
+
+// diagnostic returns the diagnostic for the given statement cost, if there is one.
+func (a *OnlineDDL) diagnostic(p *sqlcheck.Pass, stmt *migrate.Stmt, t *schema.Table, cost ddlCost) (sqlcheck.Diagnostic, bool) {
+	var algo, lock string
+	if m := reAlgorithm.FindStringSubmatch(stmt.Text); m != nil {
+		algo = strings.ToUpper(m[1])
+	}
+	if m := reLock.FindStringSubmatch(stmt.Text); m != nil {
+		lock = strings.ToUpper(m[1])
+	}
+	what := strings.Join(cost.reasons, ", ")
+	what = strings.ToUpper(what[:1]) + what[1:]
+	switch {
+	// Pinning an online algorithm or lock mode guarantees the
+	// server fails the statement rather than copying the table.
+	case cost.algo == algoCopy && (algo == "INSTANT" || algo == "INPLACE" || algo == "NOCOPY" || lock == "NONE"):
+	case cost.algo == algoCopy:
+		return sqlcheck.Diagnostic{
+			Code: codeTableCopy,
+			Pos:  stmt.Pos,
+			Text: fmt.Sprintf("%s on table %q requires ALGORITHM=COPY, which rebuilds the table and blocks concurrent writes", what, t.Name),
+		}, true
+	case cost.shared && lock == "NONE":
+	case cost.shared:
+		return sqlcheck.Diagnostic{
+			Code: codeBlockWrites,
+			Pos:  stmt.Pos,
+			Text: fmt.Sprintf("%s on table %q runs in place, but blocks concurrent writes (LOCK=SHARED)", what, t.Name),
+		}, true
+	// Clauses can be appended only to ALTER TABLE statements.
+	case a.Suggest && algo == "" && lock == "" && reAlterTable.MatchString(stmt.Text):
+		clause := "ALGORITHM=INPLACE, LOCK=NONE"
+		if cost.algo == algoInstant {
+			clause = "ALGORITHM=INSTANT"
+		}
+		d := sqlcheck.Diagnostic{
+			Code: codeOnlineDDL,
+			Pos:  stmt.Pos,
+			Text: fmt.Sprintf("%s on table %q can run with %s", what, t.Name, clause),
+		}
+		d.SuggestFix(
+			fmt.Sprintf("Add %s to the statement to prevent the server from falling back to a table copy", clause),
+			pinClause(p.File, stmt, clause),
+		)
+		return d, true
+	}
+	return sqlcheck.Diagnostic{}, false
+}
+
+// pinClause returns the text edit that appends the given clause to the statement.
+// Text that shares the lines of the statement, such as comments, is kept as-is.
+func pinClause(f *sqlcheck.File, stmt *migrate.Stmt, clause string) *sqlcheck.TextEdit {
+	b := f.Bytes()
+	end := stmt.Pos + len(stmt.Text)
+	if stmt.Pos < 0 || end > len(b) || stmt.Text == "" || string(b[stmt.Pos:end]) != stmt.Text {
+		return nil
+	}
+	var (
+		start = bytes.LastIndexByte(b[:stmt.Pos], '\n') + 1
+		last  = len(b)
+	)
+	if i := bytes.IndexByte(b[end:], '\n'); i != -1 {
+		last = end + i
+	}
+	text, semi := strings.CutSuffix(strings.TrimRightFunc(stmt.Text, unicode.IsSpace), ";")
+	text = fmt.Sprintf("%s, %s", strings.TrimRightFunc(text, unicode.IsSpace), clause)
+	if semi {
+		text += ";"
+	}
+	line := bytes.Count(b[:stmt.Pos], []byte("\n")) + 1
+	return &sqlcheck.TextEdit{
+		Line:    line,
+		End:     line + strings.Count(stmt.Text, "\n"),
+		NewText: string(b[start:stmt.Pos]) + text + string(b[end:last]),
+	}
+}
+
+// innoDB reports if the table uses the InnoDB engine. Tables
+// without the engine attribute use the server default engine.
+func innoDB(t *schema.Table) bool {
+	var e mysql.Engine
+	return !sqlx.Has(t.Attrs, &e) || e.Default || strings.EqualFold(e.V, mysql.EngineInnoDB)
+}
+
+// The algorithms InnoDB uses for executing ALTER TABLE
+// operations, ordered from the cheapest to the most expensive.
+const (
+	algoInstant ddlAlgo = iota // Metadata only change.
+	algoInplace                // In place, without rebuilding the table.
+	algoRebuild                // In place, rebuilding the table.
+	algoCopy                   // Copy the table to a new one.
+)
+
+type (
+	// ddlAlgo describes an online DDL algorithm.
+	ddlAlgo uint8
+
+	// ddlOp describes the cost of executing a single change.
+	ddlOp struct {
+		algo   ddlAlgo
+		shared bool // Concurrent writes are blocked (LOCK=SHARED).
+	}
+
+	// ddlCost describes the cost of executing an ALTER TABLE statement.
+	ddlCost struct {
+		ddlOp
+		reasons []string // The changes that determined the cost.
+	}
+)
+
+var (
+	copyOp    = ddlOp{algo: algoCopy, shared: true}
+	rebuildOp = ddlOp{algo: algoRebuild}
+	inplaceOp = ddlOp{algo: algoInplace}
+)
+
+// instantIf returns an instant operation if ok is true, or the given fallback otherwise.
+func instantIf(ok bool, fallback ddlOp) ddlOp {
+	if ok {
+		return ddlOp{algo: algoInstant}
+	}
+	return fallback
+}
+
+// rank orders operations by their impact on concurrent writes.
+func (o ddlOp) rank() int {
+	switch {
+	case o.algo == algoCopy:
+		return int(algoCopy) + 1
+	case o.shared:
+		return int(algoCopy)
+	default:
+		return int(o.algo)
+	}
+}
+
+// add returns the cost of executing the given operation along with the current
+// ones. Only the operations that determine the final cost are kept as reasons.
+func (c ddlCost) add(op ddlOp, reason string) ddlCost {
+	switch r := op.rank(); {
+	case len(c.reasons) == 0 || r > c.rank():
+		return ddlCost{ddlOp: op, reasons: []string{reason}}
+	case r == c.rank():
+		c.reasons = append(c.reasons, reason)
+	}
+	return c
+}
+
+// tableCost returns the cost of executing the given table changes.
+func tableCost(drv *mysql.Driver, t *schema.Table, stmt string, changes []schema.Change) (cost ddlCost) {
+	var (
+		reorder  = reColumnPos.MatchString(stmt)
+		fulltext = slices.ContainsFunc(t.Indexes, func(idx *schema.Index) bool {
+			var it mysql.IndexType
+			return sqlx.Has(idx.Attrs, &it) && strings.EqualFold(it.T, mysql.IndexTypeFullText)
+		})
+	)
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddColumn:
+			var (
+				x    schema.GeneratedExpr
+				op   ddlOp
+				what = fmt.Sprintf("adding column %q", c.C.Name)
+			)
+			switch {
+			case sqlx.Has(c.C.Attrs, &mysql.AutoIncrement{}):
+				op = ddlOp{algo: algoRebuild, shared: true}
+				what = fmt.Sprintf("adding AUTO_INCREMENT column %q", c.C.Name)
+			case sqlx.Has(c.C.Attrs, &x) && !strings.EqualFold(x.Type, "VIRTUAL"):
+				op = copyOp
+				what = fmt.Sprintf("adding stored generated column %q", c.C.Name)
+			case sqlx.Has(c.C.Attrs, &x):
+				op = instantIf(drv.GTE("8.0.12") || drv.Maria(), inplaceOp)
+			case fulltext:
+				// Tables with FULLTEXT indexes do not support instant ADD COLUMN.
+				op = rebuildOp
+			case reorder:
+				op = instantIf(versionGTE(drv, "8.0.29", "10.4"), rebuildOp)
+			default:
+				op = instantIf(versionGTE(drv, "8.0.12", "10.3.2"), rebuildOp)
+			}
+			cost = cost.add(op, what)
+		case *schema.DropColumn:
+			var (
+				x  schema.GeneratedExpr
+				op = instantIf(versionGTE(drv, "8.0.29", "10.4"), rebuildOp)
+			)
+			if sqlx.Has(c.C.Attrs, &x) && strings.EqualFold(x.Type, "VIRTUAL") {
+				op = instantIf(drv.GTE("8.0.12") || drv.Maria(), inplaceOp)
+			}
+			cost = cost.add(op, fmt.Sprintf("dropping column %q", c.C.Name))
+		case *schema.RenameColumn:
+			cost = cost.add(instantIf(versionGTE(drv, "8.0.28", "10.5.2"), inplaceOp), fmt.Sprintf("renaming column %q", c.From.Name))
+		case *schema.ModifyColumn:
+			if c.From.Name != c.To.Name {
+				cost = cost.add(instantIf(versionGTE(drv, "8.0.28", "10.5.2"), inplaceOp), fmt.Sprintf("renaming column %q", c.From.Name))
+			}
+			if reorder {
+				cost = cost.add(rebuildOp, fmt.Sprintf("reordering column %q", c.To.Name))
+			}
+			cost = modifyColumnCost(drv, t, c, cost)
+		case *schema.AddIndex:
+			cost = cost.add(indexOp(c.I), fmt.Sprintf("adding index %q", c.I.Name))
+		case *schema.ModifyIndex:
+			cost = cost.add(indexOp(c.To), fmt.Sprintf("modifying index %q", c.To.Name))
+		case *schema.DropIndex:
+			cost = cost.add(inplaceOp, fmt.Sprintf("dropping index %q", c.I.Name))
+		case *schema.RenameIndex:
+			cost = cost.add(inplaceOp, fmt.Sprintf("renaming index %q", c.From.Name))
+		case *schema.AddPrimaryKey:
+			cost = cost.add(rebuildOp, "adding a primary key")
+		case *schema.ModifyPrimaryKey:
+			cost = cost.add(rebuildOp, "changing the primary key")
+		case *schema.DropPrimaryKey:
+			// Dropping a primary key without adding a new
+			// one in the same statement requires a table copy.
+			if !slices.ContainsFunc(changes, func(c schema.Change) bool {
+				_, ok := c.(*schema.AddPrimaryKey)
+				return ok
+			}) {
+				cost = cost.add(copyOp, "dropping the primary key")
+			}
+		case *schema.AddForeignKey:
+			// INPLACE is supported only when foreign_key_checks is disabled.
+			cost = cost.add(copyOp, fmt.Sprintf("adding foreign key %q", c.F.Symbol))
+		case *schema.DropForeignKey:
+			cost = cost.add(inplaceOp, fmt.Sprintf("dropping foreign key %q", c.F.Symbol))
+		case *schema.AddAttr:
+			cost = tableAttrCost(c.A, cost)
+		case *schema.ModifyAttr:
+			cost = tableAttrCost(c.To, cost)
+		}
+	}
+	return cost
+}
+
+// modifyColumnCost returns the cost of modifying the given column.
+func modifyColumnCost(drv *mysql.Driver, t *schema.Table, c *schema.ModifyColumn, cost ddlCost) ddlCost {
+	name := c.To.Name
+	switch {
+	case c.Change.Is(schema.ChangeCharset) || c.Change.Is(schema.ChangeCollate):
+		cost = cost.add(copyOp, fmt.Sprintf("changing the character set of column %q", name))
+	case c.Change.Is(schema.ChangeType):
+		switch {
+		case varcharExtended(t, c.From, c.To):
+			cost = cost.add(inplaceOp, fmt.Sprintf("extending the size of column %q", name))
+		case valuesAppended(c.From.Type.Type, c.To.Type.Type):
+			cost = cost.add(instantIf(drv.GTE("8.0.12") || drv.Maria(), inplaceOp), fmt.Sprintf("adding values to column %q", name))
+		default:
+			cost = cost.add(copyOp, fmt.Sprintf("changing the type of column %q", name))
+		}
+	}
+	if c.Change.Is(schema.ChangeGenerated) {
+		cost = cost.add(copyOp, fmt.Sprintf("changing the generated expression of column %q", name))
+	}
+	if c.Change.Is(schema.ChangeNull) {
+		cost = cost.add(rebuildOp, fmt.Sprintf("changing the nullability of column %q", name))
+	}
+	if c.Change.Is(schema.ChangeDefault) {
+		cost = cost.add(instantIf(versionGTE(drv, "8.0.12", "10.3.2"), inplaceOp), fmt.Sprintf("changing the default value of column %q", name))
+	}
+	if c.Change.Is(schema.ChangeComment) {
+		cost = cost.add(inplaceOp, fmt.Sprintf("changing the comment of column %q", name))
+	}
+	if c.Change.Is(schema.ChangeAttr) && sqlx.Has(c.To.Attrs, &mysql.AutoIncrement{}) && !sqlx.Has(c.From.Attrs, &mysql.AutoIncrement{}) {
+		cost = cost.add(copyOp, fmt.Sprintf("adding AUTO_INCREMENT to column %q", name))
+	}
+	return cost
+}
+
+// tableAttrCost returns the cost of changing the given table attribute.
+func tableAttrCost(a schema.Attr, cost ddlCost) ddlCost {
+	switch a := a.(type) {
+	case *schema.Charset:
+		cost = cost.add(rebuildOp, fmt.Sprintf("changing the table character set to %q", a.V))
+	case *schema.Collation:
+		cost = cost.add(rebuildOp, fmt.Sprintf("changing the table collation to %q", a.V))
+	case *mysql.Engine:
+		cost = cost.add(copyOp, fmt.Sprintf("changing the table engine to %q", a.V))
+	case *schema.Comment:
+		cost = cost.add(inplaceOp, "changing the table comment")
+	case *mysql.AutoIncrement:
+		cost = cost.add(inplaceOp, "changing the AUTO_INCREMENT value")
+	}
+	return cost
+}
+
+// indexOp returns the cost of creating the given index.
+func indexOp(idx *schema.Index) ddlOp {
+	var it mysql.IndexType
+	if sqlx.Has(idx.Attrs, &it) && (strings.EqualFold(it.T, mysql.IndexTypeFullText) || strings.EqualFold(it.T, mysql.IndexTypeSpatial)) {
+		return ddlOp{algo: algoInplace, shared: true}
+	}
+	return inplaceOp
+}
+
+// versionGTE reports if the server version is greater than or equal
+// to the given MySQL version or MariaDB version, based on its flavor.
+func versionGTE(drv *mysql.Driver, my, maria string) bool {
+	if drv.Maria() {
+		return drv.GTE(maria)
+	}
+	return drv.GTE(my)
+}
+
+// varcharExtended reports if a VARCHAR column was extended without
+// changing the number of bytes used to store the length prefix.
+func varcharExtended(t *schema.Table, from, to *schema.Column) bool {
+	s1, ok1 := from.Type.Type.(*schema.StringType)
+	s2, ok2 := to.Type.Type.(*schema.StringType)
+	if !ok1 || !ok2 || s1.T != mysql.TypeVarchar || s2.T != mysql.TypeVarchar || s2.Size < s1.Size {
+		return false
+	}
+	n := maxCharLen(t, to)
+	return (s1.Size*n < 256) == (s2.Size*n < 256)
+}
+
+// maxCharLen returns the maximum number of bytes per
+// character based on the column or table character set.
+func maxCharLen(t *schema.Table, c *schema.Column) int {
+	var cs schema.Charset
+	if !sqlx.Has(c.Attrs, &cs) && !sqlx.Has(t.Attrs, &cs) {
+		return 4
+	}
+	switch cs := strings.ToLower(cs.V); {
+	case cs == "latin1" || cs == "ascii" || cs == "binary":
+		return 1
+	case cs == "ucs2":
+		return 2
+	case cs == "utf8" || cs == "utf8mb3":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// valuesAppended reports if values were appended to the end of an ENUM
+// or SET definition without changing the storage size of the column.
+func valuesAppended(from, to schema.Type) bool {
+	switch from := from.(type) {
+	case *schema.EnumType:
+		to, ok := to.(*schema.EnumType)
+		return ok && len(to.Values) > len(from.Values) && slices.Equal(from.Values, to.Values[:len(from.Values)]) &&
+			(len(from.Values) < 256) == (len(to.Values) < 256)
+	case *mysql.SetType:
+		to, ok := to.(*mysql.SetType)
+		return ok && len(to.Values) > len(from.Values) && slices.Equal(from.Values, to.Values[:len(from.Values)]) &&
+			setBytes(len(from.Values)) == setBytes(len(to.Values))
+	}
+	return false
+}
+
+// setBytes returns the storage size of a SET column with n members.
+func setBytes(n int) int {
+	if b := (n + 7) / 8; b <= 4 {
+		return b
+	}
+	return 8
+}
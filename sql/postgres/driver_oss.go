@@ -9,6 +9,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"math/rand"
@@ -180,6 +181,42 @@ func (d *Driver) Lock(ctx context.Context, name string, timeout time.Duration) (
 	}, nil
 }
 
+// SetStmtTimeout implements migrate.StmtTimeoutSetter. The statement_timeout and lock_timeout
+// settings are set with SET LOCAL, and therefore apply only to statements executed in a transaction.
+func (d *Driver) SetStmtTimeout(ctx context.Context, timeout time.Duration) (migrate.RestoreFunc, error) {
+	if !sqlx.IsSingleConn(d.ExecQuerier) {
+		return func(context.Context) error { return nil }, nil
+	}
+	rows, err := d.QueryContext(ctx, "SELECT current_setting('statement_timeout'), current_setting('lock_timeout')")
+	if err != nil {
+		return nil, err
+	}
+	var stmtT, lockT string
+	if err := sqlx.ScanOne(rows, &stmtT, &lockT); err != nil {
+		return nil, err
+	}
+	ms := strconv.FormatInt(max(timeout.Milliseconds(), 1), 10)
+	if err := setLocalTimeouts(ctx, d, ms, ms); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		return setLocalTimeouts(ctx, d, stmtT, lockT)
+	}, nil
+}
+
+// setLocalTimeouts sets the statement and lock timeouts of the current transaction.
+func setLocalTimeouts(ctx context.Context, conn schema.ExecQuerier, stmtT, lockT string) error {
+	for _, s := range []string{
+		"SET LOCAL statement_timeout = " + quote(stmtT),
+		"SET LOCAL lock_timeout = " + quote(lockT),
+	} {
+		if _, err := conn.ExecContext(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Snapshot implements migrate.Snapshoter.
 func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	// Postgres will only then be considered bound to a schema if the `search_path` was given.
@@ -373,6 +410,22 @@ func (*Driver) ScanStmts(input string) ([]*migrate.Stmt, error) {
 	}).Scan(input)
 }
 
+// IsRetryable reports if the given statement execution error is transient, and the statement
+// can be retried: serialization failures (40001), deadlocks (40P01) and lock timeouts (55P03).
+// It is intended to be used with migrate.WithRetryPolicy.
+func IsRetryable(err error) bool {
+	var e interface{ SQLState() string }
+	if !errors.As(err, &e) {
+		return false
+	}
+	switch e.SQLState() {
+	case "40001", "40P01", "55P03":
+		return true
+	default:
+		return false
+	}
+}
+
 // Use pg_try_advisory_lock to avoid deadlocks between multiple executions of Atlas (commonly tests).
 // The common case is as follows: a process (P1) of Atlas takes a lock, and another process (P2) of
 // Atlas waits for the lock. Now if P1 execute "CREATE INDEX CONCURRENTLY" (either in apply or diff),
@@ -8,6 +8,8 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -225,6 +227,49 @@ func TestDriver_RealmRestoreFunc(t *testing.T) {
 	require.Equal(t, "t1", dropT.T.Name)
 }
 
+func TestDriver_SetStmtTimeout(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Session settings are not set on a connection pool.
+	d := &Driver{conn: &conn{ExecQuerier: db}}
+	restore, err := d.SetStmtTimeout(ctx, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, restore(ctx))
+
+	m.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape("SELECT current_setting('statement_timeout'), current_setting('lock_timeout')")).
+		WillReturnRows(sqlmock.NewRows([]string{"statement_timeout", "lock_timeout"}).AddRow("0", "10s"))
+	m.ExpectExec(sqltest.Escape("SET LOCAL statement_timeout = '1500'")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET LOCAL lock_timeout = '1500'")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET LOCAL statement_timeout = '0'")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("SET LOCAL lock_timeout = '10s'")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d = &Driver{conn: &conn{ExecQuerier: tx}}
+	restore, err = d.SetStmtTimeout(ctx, 1500*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, restore(ctx))
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestIsRetryable(t *testing.T) {
+	for code, ok := range map[string]bool{"40001": true, "40P01": true, "55P03": true, "23505": false} {
+		require.Equal(t, ok, IsRetryable(fmt.Errorf("exec: %w", sqlStateError(code))), code)
+	}
+	require.False(t, IsRetryable(errors.New("deadlock detected")))
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "pq: " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
 type mockInspector struct {
 	schema.Inspector
 	realm  *schema.Realm
@@ -5,15 +5,27 @@
 package postgrescheck
 
 import (
+	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/brandedcheck"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
+	"ariga.io/atlas/sql/sqlcheck/custom"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
+	"ariga.io/atlas/sql/sqlcheck/dataloss"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
+	"ariga.io/atlas/sql/sqlcheck/dmlsafety"
 	"ariga.io/atlas/sql/sqlcheck/incompatible"
+	"ariga.io/atlas/sql/sqlcheck/naming"
+	"ariga.io/atlas/sql/sqlcheck/structural"
+	"ariga.io/atlas/sql/sqlcheck/txsafety"
 )
 
 func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err error) {
@@ -32,6 +44,135 @@ func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err erro
 	}, nil
 }
 
+// stringSize returns the maximum number of characters a string column can hold.
+func stringSize(t *schema.StringType) int64 {
+	switch t.T {
+	case postgres.TypeVarChar, postgres.TypeCharVar:
+		if t.Size == 0 {
+			return -1
+		}
+		return int64(t.Size)
+	case postgres.TypeChar, postgres.TypeCharacter, postgres.TypeBPChar:
+		if t.Size == 0 {
+			return 1
+		}
+		return int64(t.Size)
+	default:
+		return -1
+	}
+}
+
+// integerSize returns the storage size in bytes of an integer type.
+func integerSize(t *schema.IntegerType) int {
+	switch t.T {
+	case postgres.TypeSmallInt, postgres.TypeInt2:
+		return 2
+	case postgres.TypeInteger, postgres.TypeInt, postgres.TypeInt4:
+		return 4
+	case postgres.TypeBigInt, postgres.TypeInt8:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// decimalSize returns the effective precision and scale of a decimal type.
+func decimalSize(t *schema.DecimalType) (int, int) {
+	if t.Precision == 0 {
+		// NUMERIC without precision can store values of any precision.
+		return -1, 0
+	}
+	return t.Precision, t.Scale
+}
+
+// timePrecision returns the fractional seconds precision of a time type.
+func timePrecision(t *schema.TimeType) int {
+	switch {
+	case t.T == postgres.TypeDate:
+		return -1
+	case t.Precision != nil:
+		return *t.Precision
+	default:
+		// The default precision of time types is 6 (microseconds).
+		return 6
+	}
+}
+
+// noTxStmts lists the statements that cannot be executed inside a transaction block.
+var noTxStmts = []struct {
+	re   *regexp.Regexp
+	what string
+}{
+	{regexp.MustCompile(`(?i)^\s*CREATE\s+(UNIQUE\s+)?INDEX\s+CONCURRENTLY\s`), "CREATE INDEX CONCURRENTLY"},
+	{regexp.MustCompile(`(?i)^\s*DROP\s+INDEX\s+CONCURRENTLY\s`), "DROP INDEX CONCURRENTLY"},
+	{regexp.MustCompile(`(?i)^\s*REINDEX\s+(\([^)]*\)\s*)?\w+\s+CONCURRENTLY\s`), "REINDEX CONCURRENTLY"},
+	{regexp.MustCompile(`(?i)^\s*ALTER\s+TABLE\s.+\sDETACH\s+PARTITION\s.+\sCONCURRENTLY\s*;?\s*$`), "DETACH PARTITION CONCURRENTLY"},
+	{regexp.MustCompile(`(?i)^\s*VACUUM\b`), "VACUUM"},
+	{regexp.MustCompile(`(?i)^\s*(CREATE|DROP)\s+DATABASE\s`), "CREATE/DROP DATABASE"},
+	{regexp.MustCompile(`(?i)^\s*(CREATE|DROP)\s+TABLESPACE\s`), "CREATE/DROP TABLESPACE"},
+	{regexp.MustCompile(`(?i)^\s*ALTER\s+SYSTEM\s`), "ALTER SYSTEM"},
+}
+
+// reAddEnumValue matches the ALTER TYPE ... ADD VALUE statement.
+var reAddEnumValue = regexp.MustCompile(`(?i)^\s*ALTER\s+TYPE\s.+\sADD\s+VALUE\s`)
+
+// noTx reports if the statement cannot be executed inside a transaction block.
+func noTx(p *sqlcheck.Pass, s *migrate.Stmt) (string, bool) {
+	for _, n := range noTxStmts {
+		if n.re.MatchString(s.Text) {
+			return n.what, true
+		}
+	}
+	// Before PostgreSQL 12, ALTER TYPE ... ADD VALUE
+	// cannot be executed inside a transaction block.
+	if reAddEnumValue.MatchString(s.Text) && p.Dev != nil {
+		if drv, ok := p.Dev.Driver.(interface{ Version() string }); ok {
+			if v, err := strconv.Atoi(drv.Version()); err == nil && v < 12_00_00 {
+				return "ALTER TYPE ... ADD VALUE", true
+			}
+		}
+	}
+	return "", false
+}
+
+// tableStats reads the estimated number of rows and the total size of the tables in
+// the database, as collected by the last VACUUM or ANALYZE of each table. Tables
+// that were never analyzed have an unknown (-1) number of rows, and are skipped.
+func tableStats(ctx context.Context, conn schema.ExecQuerier) (map[string]sqlcheck.TableStats, error) {
+	rows, err := conn.QueryContext(ctx, `
+SELECT
+	n.nspname,
+	c.relname,
+	c.reltuples::bigint,
+	pg_catalog.pg_total_relation_size(c.oid)
+FROM
+	pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE
+	c.relkind IN ('r', 'p')
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND n.nspname NOT LIKE 'pg_toast%'
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := make(map[string]sqlcheck.TableStats)
+	for rows.Next() {
+		var (
+			s, t    string
+			n, size int64
+		)
+		if err := rows.Scan(&s, &t, &n, &size); err != nil {
+			return nil, err
+		}
+		if n >= 0 {
+			stats[s+"."+t] = sqlcheck.TableStats{Rows: n, Bytes: size}
+		}
+	}
+	return stats, rows.Err()
+}
+
 func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	ds, err := destructive.New(r)
 	if err != nil {
@@ -51,5 +192,40 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return []sqlcheck.Analyzer{ds, dd, cd, bc}, nil
+	dl, err := dataloss.New(r, dataloss.Handler{
+		StringSize:    stringSize,
+		IntegerSize:   integerSize,
+		DecimalSize:   decimalSize,
+		TimePrecision: timePrecision,
+	})
+	if err != nil {
+		return nil, err
+	}
+	nm, err := naming.New(r)
+	if err != nil {
+		return nil, err
+	}
+	st, err := structural.New(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txsafety.New(r, txsafety.Handler{
+		NoTx: noTx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dm, err := dmlsafety.New(r, dmlsafety.Postgres)
+	if err != nil {
+		return nil, err
+	}
+	br, err := brandedcheck.New(r)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := custom.New(r)
+	if err != nil {
+		return nil, err
+	}
+	return []sqlcheck.Analyzer{ds, dd, cd, bc, dl, nm, st, tx, dm, br, cr}, nil
 }
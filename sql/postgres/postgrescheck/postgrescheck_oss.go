@@ -13,4 +13,5 @@ import (
 
 func init() {
 	sqlcheck.Register(postgres.DriverName, analyzers)
+	sqlcheck.RegisterStats(postgres.DriverName, tableStats)
 }
@@ -8,12 +8,15 @@ import (
 	"context"
 	"testing"
 
+	"ariga.io/atlas/sql/internal/sqltest"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/postgres"
 	_ "ariga.io/atlas/sql/postgres/postgrescheck"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlclient"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,6 +58,60 @@ func TestDataDepend_MightFail(t *testing.T) {
 	require.Equal(t, report.Diagnostics[0].Text, `Adding a non-nullable "int" column "b" will fail in case table "users" is not empty`)
 }
 
+func TestTxSafety_NoTx(t *testing.T) {
+	for _, tt := range []struct {
+		version string
+		diags   []string
+	}{
+		{
+			version: "110000",
+			diags:   []string{"CREATE INDEX CONCURRENTLY", "ALTER TYPE ... ADD VALUE", "VACUUM"},
+		},
+		{
+			// ALTER TYPE ... ADD VALUE can be executed in a transaction block since PostgreSQL 12.
+			version: "150000",
+			diags:   []string{"CREATE INDEX CONCURRENTLY", "VACUUM"},
+		},
+	} {
+		db, mk, err := sqlmock.New()
+		require.NoError(t, err)
+		mk.ExpectQuery(sqltest.Escape("SELECT current_setting('server_version_num'), current_setting('default_table_access_method', true), current_setting('crdb_version', true)")).
+			WillReturnRows(sqltest.Rows(`
+  version_num | am   | crdb
+--------------+------+------
+ ` + tt.version + `       | heap | NULL
+`))
+		drv, err := postgres.Open(db)
+		require.NoError(t, err)
+		var (
+			report *sqlcheck.Report
+			text   = "CREATE INDEX CONCURRENTLY i ON t(c);\nALTER TYPE e ADD VALUE 'b';\nCREATE INDEX j ON t(c);\nVACUUM t;\n"
+			f      = migrate.NewLocalFile("1.sql", []byte(text))
+		)
+		stmts, err := f.StmtDecls()
+		require.NoError(t, err)
+		changes := make([]*sqlcheck.Change, len(stmts))
+		for i, s := range stmts {
+			changes[i] = &sqlcheck.Change{Stmt: s}
+		}
+		azs, err := sqlcheck.AnalyzerFor(postgres.DriverName, nil)
+		require.NoError(t, err)
+		require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), &sqlcheck.Pass{
+			Dev:  &sqlclient.Client{Name: postgres.DriverName, Driver: drv},
+			File: &sqlcheck.File{File: f, Changes: changes},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}))
+		require.Equal(t, "transaction safety issues detected", report.Text)
+		require.Len(t, report.Diagnostics, len(tt.diags))
+		for i, d := range tt.diags {
+			require.Equal(t, "TX101", report.Diagnostics[i].Code)
+			require.Equal(t, d+" cannot be executed inside a transaction block, but the file is executed in a transaction", report.Diagnostics[i].Text)
+		}
+	}
+}
+
 type testFile struct {
 	name string
 	migrate.File
@@ -0,0 +1,248 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package brandedcheck implements an analyzer for branded ID columns.
+package brandedcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ibranded "ariga.io/atlas/internal/branded"
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/branded"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// Analyzer checks the branded ID columns and foreign keys that were added or
+// modified by the file, as they are defined at the end of the file. For example:
+//
+//	lint {
+//	  branded {
+//	    strict = true
+//	    naming = true
+//	  }
+//	}
+type Analyzer struct {
+	sqlcheck.Options
+
+	// Strict reports branded ID columns with unknown namespaces. Defaults to true.
+	Strict bool
+	// Naming reports branded ID columns that do not follow the naming convention.
+	Naming bool
+}
+
+// New creates a new branded ID Analyzer with the given options.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{Strict: true}
+	r, ok := r.Resource(az.Name())
+	if !ok {
+		return az, nil
+	}
+	if err := r.As(&az.Options); err != nil {
+		return nil, fmt.Errorf("sql/sqlcheck: parsing branded check options: %w", err)
+	}
+	for n, v := range map[string]*bool{"strict": &az.Strict, "naming": &az.Naming} {
+		a, ok := az.Remain().Attr(n)
+		if !ok {
+			continue
+		}
+		b, err := a.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing branded %s option: %w", n, err)
+		}
+		*v = b
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeUnknownNS  = sqlcheck.Code("BR101")
+	codeFKMismatch = sqlcheck.Code("BR102")
+	codeNaming     = sqlcheck.Code("BR103")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "branded"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	if p.File.To == nil {
+		return nil
+	}
+	var (
+		diags, naming []sqlcheck.Diagnostic
+		strict        = ibranded.NewValidator(ibranded.WithStrict(a.Strict))
+		convention    = ibranded.NewValidator(ibranded.WithStrict(false), ibranded.WithNamingConvention(true))
+	)
+	cols, fks := changedObjects(p.File)
+	for _, c := range cols {
+		t, ok := tableOf(p.File.To, c.t)
+		if !ok {
+			continue
+		}
+		col, ok := t.Column(c.name)
+		if !ok {
+			continue
+		}
+		col = brandedColumn(col)
+		for _, e := range strict.ValidateColumn(t.Name, col) {
+			diags = append(diags, sqlcheck.Diagnostic{
+				Code: codeUnknownNS,
+				Pos:  c.pos,
+				Text: fmt.Sprintf("Branded ID column %q on table %q: %s", col.Name, t.Name, e.Message),
+			})
+		}
+		if !a.Naming {
+			continue
+		}
+		for _, e := range convention.ValidateColumn(t.Name, col) {
+			naming = append(naming, sqlcheck.Diagnostic{
+				Code: codeNaming,
+				Pos:  c.pos,
+				Text: fmt.Sprintf("Branded ID column %q on table %q does not follow the naming convention: %s", col.Name, t.Name, e.Message),
+			})
+		}
+	}
+	for _, c := range fks {
+		t, ok := tableOf(p.File.To, c.t)
+		if !ok {
+			continue
+		}
+		fk, ok := t.ForeignKey(c.name)
+		if !ok {
+			continue
+		}
+		for _, e := range strict.ValidateForeignKey(brandedFK(fk)) {
+			diags = append(diags, sqlcheck.Diagnostic{
+				Code: codeFKMismatch,
+				Pos:  c.pos,
+				Text: fmt.Sprintf("Foreign key %q on table %q: %s", fk.Symbol, t.Name, e.Message),
+			})
+		}
+	}
+	// Naming violations are never reported as errors.
+	if len(naming) > 0 {
+		p.Reporter.WriteReport(sqlcheck.Report{Text: "branded ID naming violations detected", Diagnostics: naming})
+	}
+	if len(diags) > 0 {
+		const reportText = "branded ID issues detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// A table object that was changed by a statement.
+type changed struct {
+	pos  int
+	t    *schema.Table
+	name string
+}
+
+// changedObjects returns the columns and foreign keys that were added or modified
+// by the file. Objects that were changed by multiple statements are returned once.
+func changedObjects(f *sqlcheck.File) (cols, fks []changed) {
+	seen := make(map[string]bool)
+	add := func(objs *[]changed, kind string, pos int, t *schema.Table, name string) {
+		k := fmt.Sprintf("%s:%s.%s", kind, t.Name, name)
+		if t.Schema != nil {
+			k = t.Schema.Name + "." + k
+		}
+		if !seen[k] {
+			seen[k] = true
+			*objs = append(*objs, changed{pos: pos, t: t, name: name})
+		}
+	}
+	for _, sc := range f.Changes {
+		if sc.Stmt == nil {
+			continue
+		}
+		for _, c := range sc.Changes {
+			switch c := c.(type) {
+			case *schema.AddTable:
+				for _, col := range c.T.Columns {
+					add(&cols, "column", sc.Stmt.Pos, c.T, col.Name)
+				}
+				for _, fk := range c.T.ForeignKeys {
+					add(&fks, "fk", sc.Stmt.Pos, c.T, fk.Symbol)
+				}
+			case *schema.ModifyTable:
+				for _, mc := range c.Changes {
+					switch mc := mc.(type) {
+					case *schema.AddColumn:
+						add(&cols, "column", sc.Stmt.Pos, c.T, mc.C.Name)
+					case *schema.ModifyColumn:
+						add(&cols, "column", sc.Stmt.Pos, c.T, mc.To.Name)
+					case *schema.RenameColumn:
+						add(&cols, "column", sc.Stmt.Pos, c.T, mc.To.Name)
+					case *schema.AddForeignKey:
+						add(&fks, "fk", sc.Stmt.Pos, c.T, mc.F.Symbol)
+					case *schema.ModifyForeignKey:
+						add(&fks, "fk", sc.Stmt.Pos, c.T, mc.To.Symbol)
+					}
+				}
+			}
+		}
+	}
+	return cols, fks
+}
+
+// tableOf returns the table from the given realm.
+func tableOf(r *schema.Realm, t *schema.Table) (*schema.Table, bool) {
+	switch {
+	case t.Schema != nil:
+		if s, ok := r.Schema(t.Schema.Name); ok {
+			return s.Table(t.Name)
+		}
+	case len(r.Schemas) == 1:
+		return r.Schemas[0].Table(t.Name)
+	}
+	return nil, false
+}
+
+// brandedColumn returns the column with its branded ID type. Columns that were
+// inspected from the database are stored as varchar(14), and their namespace is
+// kept in the column comment.
+func brandedColumn(c *schema.Column) *schema.Column {
+	if c.Type == nil {
+		return &schema.Column{Name: c.Name, Type: &schema.ColumnType{}}
+	}
+	st, ok := c.Type.Type.(*schema.StringType)
+	if !ok || st.Size != 14 {
+		return c
+	}
+	var cm schema.Comment
+	if !sqlx.Has(c.Attrs, &cm) {
+		return c
+	}
+	ns, ok := branded.ParseComment(cm.Text)
+	if !ok {
+		return c
+	}
+	bc := *c
+	bc.Type = &schema.ColumnType{Type: branded.BrandedIDFromNamespace(ns), Raw: c.Type.Raw, Null: c.Type.Null}
+	return &bc
+}
+
+// brandedFK returns the foreign key with branded ID types set on its columns.
+func brandedFK(fk *schema.ForeignKey) *schema.ForeignKey {
+	bfk := *fk
+	bfk.Columns, bfk.RefColumns = make([]*schema.Column, len(fk.Columns)), make([]*schema.Column, len(fk.RefColumns))
+	for i, c := range fk.Columns {
+		bfk.Columns[i] = brandedColumn(c)
+	}
+	for i, c := range fk.RefColumns {
+		bfk.RefColumns[i] = brandedColumn(c)
+	}
+	return &bfk
+}
@@ -0,0 +1,128 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package brandedcheck_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/branded"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/brandedcheck"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_Branded(t *testing.T) {
+	var (
+		s     = schema.New("public")
+		tasks = schema.NewTable("tasks").AddColumns(
+			schema.NewColumn("id").SetType(branded.BrandedID("TSK")),
+			schema.NewColumn("owner").SetType(branded.BrandedID("XYZ")),
+		)
+		// Inspected columns keep their namespace in the comment.
+		epics = schema.NewTable("epics").AddColumns(
+			schema.NewStringColumn("id", "varchar", schema.StringSize(14)).
+				SetComment(branded.FormatComment("EPC")),
+			schema.NewStringColumn("task", "varchar", schema.StringSize(14)).
+				SetComment(branded.FormatComment("EPC")),
+		)
+		// Columns that were not changed by the file are not checked.
+		users = schema.NewTable("users").AddColumns(
+			schema.NewColumn("id").SetType(branded.BrandedID("ABC")),
+			schema.NewColumn("tsk_id").SetType(branded.BrandedID("TSK")),
+		)
+	)
+	s.AddTables(tasks, epics, users)
+	epics.AddForeignKeys(
+		schema.NewForeignKey("epics_task").AddColumns(epics.Columns[1]).SetRefTable(tasks).AddRefColumns(tasks.Columns[0]),
+	)
+	var (
+		reports []sqlcheck.Report
+		pass    = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: &sqlcheck.File{
+				File: migrate.NewLocalFile("1.sql", nil),
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt:    &migrate.Stmt{Pos: 0, Text: "CREATE TABLE tasks"},
+						Changes: schema.Changes{&schema.AddTable{T: tasks}},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 10, Text: "ALTER TABLE epics"},
+						Changes: schema.Changes{&schema.ModifyTable{T: epics, Changes: schema.Changes{
+							&schema.AddColumn{C: epics.Columns[1]},
+							&schema.AddForeignKey{F: epics.ForeignKeys[0]},
+						}}},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 20, Text: "ALTER TABLE users"},
+						Changes: schema.Changes{&schema.ModifyTable{T: users, Changes: schema.Changes{
+							&schema.AddColumn{C: users.Columns[1]},
+						}}},
+					},
+				},
+				To: schema.NewRealm(s),
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				reports = append(reports, r)
+			}),
+		}
+	)
+	az, err := brandedcheck.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "branded",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("naming", true),
+					schemahcl.BoolAttr("error", true),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.EqualError(t, az.Analyze(context.Background(), pass), "branded ID issues detected")
+	require.Len(t, reports, 2)
+
+	require.Equal(t, "branded ID naming violations detected", reports[0].Text)
+	require.Len(t, reports[0].Diagnostics, 2)
+	require.Equal(t, "BR103", reports[0].Diagnostics[0].Code)
+	require.Equal(t, `Branded ID column "owner" on table "tasks" does not follow the naming convention: recommended naming is 'id' or 'xyz_id'`, reports[0].Diagnostics[0].Text)
+	require.Equal(t, `Branded ID column "task" on table "epics" does not follow the naming convention: recommended naming is 'id' or 'epc_id'`, reports[0].Diagnostics[1].Text)
+
+	require.Equal(t, "branded ID issues detected", reports[1].Text)
+	require.Len(t, reports[1].Diagnostics, 2)
+	require.Equal(t, "BR101", reports[1].Diagnostics[0].Code)
+	require.Equal(t, 0, reports[1].Diagnostics[0].Pos)
+	require.Contains(t, reports[1].Diagnostics[0].Text, `Branded ID column "owner" on table "tasks": unknown namespace "XYZ"`)
+	require.Equal(t, sqlcheck.Diagnostic{
+		Code: "BR102",
+		Pos:  10,
+		Text: `Foreign key "epics_task" on table "epics": namespace mismatch "task" (EPC) -> "id" (TSK)`,
+	}, reports[1].Diagnostics[1])
+
+	// Unknown namespaces are allowed in non-strict mode, and
+	// diagnostics are not reported as errors by default.
+	reports = nil
+	az, err = brandedcheck.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "branded",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("strict", false),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, reports, 1)
+	require.Len(t, reports[0].Diagnostics, 1)
+	require.Equal(t, "BR102", reports[0].Diagnostics[0].Code)
+}
@@ -0,0 +1,553 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package custom implements an analyzer for lint rules that are declared
+// in the project file, instead of being registered in Go. For example:
+//
+//	lint {
+//	  rule "no_float_money" {
+//	    description = "Monetary columns must not use floating-point types"
+//	    match {
+//	      change = "add_column"
+//	      type   = "float*"
+//	      name   = "*price*"
+//	    }
+//	    severity = "error"
+//	  }
+//	}
+package custom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// Analyzer evaluates the custom rules against the changes of the file.
+	Analyzer struct {
+		Rules []*Rule
+	}
+
+	// Rule is a custom lint rule. A rule is violated by every object
+	// that matches at least one of its matchers.
+	Rule struct {
+		// Name of the rule. Used as the code of its diagnostics.
+		Name string
+		// Description of the rule. Used as the text of its report.
+		Description string
+		// Error indicates the rule severity is "error", and the
+		// analysis should fail in case the rule is violated.
+		Error bool
+		// Match holds the matchers of the rule.
+		Match []*Match
+	}
+
+	// Match describes the objects a rule applies to. Empty fields match all objects.
+	Match struct {
+		// Change kinds, for example "add_column", or actions,
+		// for example "add", that match changes of all object types.
+		Change []string
+		// Object types: "table", "column", "index", "foreign_key" or "check".
+		Object []string
+		// Name and Table are glob patterns of the object and table names.
+		Name, Table string
+		// Type is a glob pattern of the column type. For example, "varchar*".
+		Type string
+		// Comment is a glob pattern of the object comment.
+		Comment string
+		// Null, Default and Unique match columns by their nullability and default
+		// value, and indexes by their uniqueness. Unique also matches columns that
+		// are covered by a single-column unique index.
+		Null, Default, Unique *bool
+	}
+)
+
+// Object types.
+const (
+	objectTable      = "table"
+	objectColumn     = "column"
+	objectIndex      = "index"
+	objectForeignKey = "foreign_key"
+	objectCheck      = "check"
+)
+
+// New creates a new custom-rules Analyzer from the rule blocks of the given resource.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{}
+	for _, rr := range r.Resources("rule") {
+		rule, err := ruleFrom(rr)
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing rule %q: %w", rr.Name, err)
+		}
+		az.Rules = append(az.Rules, rule)
+	}
+	return az, nil
+}
+
+// ruleFrom parses a rule from the given resource.
+func ruleFrom(r *schemahcl.Resource) (*Rule, error) {
+	if r.Name == "" {
+		return nil, errors.New("missing rule name")
+	}
+	rule := &Rule{Name: r.Name}
+	if a, ok := r.Attr("description"); ok {
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("parsing description: %w", err)
+		}
+		rule.Description = s
+	}
+	if a, ok := r.Attr("severity"); ok {
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("parsing severity: %w", err)
+		}
+		switch strings.ToLower(s) {
+		case "error":
+			rule.Error = true
+		case "warning":
+		default:
+			return nil, fmt.Errorf("unknown severity %q, expect \"error\" or \"warning\"", s)
+		}
+	}
+	for _, mr := range r.Resources("match") {
+		m, err := matchFrom(mr)
+		if err != nil {
+			return nil, err
+		}
+		rule.Match = append(rule.Match, m)
+	}
+	if len(rule.Match) == 0 {
+		return nil, errors.New("missing match block")
+	}
+	return rule, nil
+}
+
+// matchFrom parses a matcher from the given resource.
+func matchFrom(r *schemahcl.Resource) (*Match, error) {
+	m := &Match{}
+	for n, v := range map[string]*[]string{"change": &m.Change, "object": &m.Object} {
+		a, ok := r.Attr(n)
+		if !ok {
+			continue
+		}
+		s, err := a.String()
+		if err == nil {
+			*v = []string{s}
+		} else if *v, err = a.Strings(); err != nil {
+			return nil, fmt.Errorf("parsing match %s: %w", n, err)
+		}
+	}
+	for _, o := range m.Object {
+		switch o {
+		case objectTable, objectColumn, objectIndex, objectForeignKey, objectCheck:
+		default:
+			return nil, fmt.Errorf("unknown match object %q", o)
+		}
+	}
+	for n, v := range map[string]*string{"name": &m.Name, "table": &m.Table, "type": &m.Type, "comment": &m.Comment} {
+		a, ok := r.Attr(n)
+		if !ok {
+			continue
+		}
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("parsing match %s: %w", n, err)
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid match %s pattern %q: %w", n, s, err)
+		}
+		*v = strings.ToLower(s)
+	}
+	for n, v := range map[string]**bool{"null": &m.Null, "default": &m.Default, "unique": &m.Unique} {
+		a, ok := r.Attr(n)
+		if !ok {
+			continue
+		}
+		b, err := a.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("parsing match %s: %w", n, err)
+		}
+		*v = &b
+	}
+	return m, nil
+}
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "rule"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	if len(a.Rules) == 0 {
+		return nil
+	}
+	var (
+		errs    []error
+		objects = objectsOf(p.File)
+	)
+	for _, r := range a.Rules {
+		var diags []sqlcheck.Diagnostic
+		for _, o := range objects {
+			if !r.matches(p, o) {
+				continue
+			}
+			text := fmt.Sprintf("%s %q", o.title(), o.name)
+			if o.kind != objectTable {
+				text += fmt.Sprintf(" on table %q", o.table.Name)
+			}
+			diags = append(diags, sqlcheck.Diagnostic{
+				Code: r.Name,
+				Pos:  o.pos,
+				Text: fmt.Sprintf("%s violates rule %q", text, r.Name),
+			})
+		}
+		if len(diags) == 0 {
+			continue
+		}
+		text := r.Description
+		if text == "" {
+			text = fmt.Sprintf("rule %q violated", r.Name)
+		}
+		p.Reporter.WriteReport(sqlcheck.Report{Text: text, Diagnostics: diags})
+		if r.Error {
+			errs = append(errs, errors.New(text))
+		}
+	}
+	// Only the first error is returned, as all
+	// rule reports were written to the reporter.
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// matches reports if the object matches one of the rule matchers.
+func (r *Rule) matches(p *sqlcheck.Pass, o *object) bool {
+	for _, m := range r.Match {
+		if m.matches(p, o) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports if the object matches all fields of the matcher.
+func (m *Match) matches(p *sqlcheck.Pass, o *object) bool {
+	switch {
+	case len(m.Change) > 0 && !matchChange(m.Change, o):
+		return false
+	case len(m.Object) > 0 && !slices.Contains(m.Object, o.kind):
+		return false
+	case m.Name != "" && !glob(m.Name, o.name):
+		return false
+	case m.Table != "" && !glob(m.Table, o.table.Name):
+		return false
+	case m.Comment != "" && !glob(m.Comment, o.comment()):
+		return false
+	}
+	if m.Type != "" || m.Null != nil || m.Default != nil {
+		// Type and value matchers apply only to columns.
+		c := o.column
+		if c == nil || c.Type == nil {
+			return false
+		}
+		if m.Type != "" && !matchType(m.Type, typeString(p, c.Type)) {
+			return false
+		}
+		if m.Null != nil && c.Type.Null != *m.Null {
+			return false
+		}
+		if m.Default != nil && (c.Default != nil) != *m.Default {
+			return false
+		}
+	}
+	if m.Unique != nil {
+		switch {
+		case o.index != nil:
+			return o.index.Unique == *m.Unique
+		case o.column != nil:
+			return uniqueColumn(o.table, o.column) == *m.Unique
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// An object that was changed by a statement in the file.
+type object struct {
+	pos    int    // position of the statement.
+	action string // e.g., "add" or "drop".
+	kind   string // object type.
+	name   string
+	table  *schema.Table
+	column *schema.Column
+	index  *schema.Index
+	attrs  []schema.Attr
+}
+
+// title returns the object type in a human-readable form.
+func (o *object) title() string {
+	switch o.kind {
+	case objectForeignKey:
+		return "Foreign key"
+	case objectCheck:
+		return "Check constraint"
+	default:
+		return strings.ToUpper(o.kind[:1]) + o.kind[1:]
+	}
+}
+
+// comment returns the comment of the object, if exists.
+func (o *object) comment() string {
+	var c schema.Comment
+	if sqlx.Has(o.attrs, &c) {
+		return c.Text
+	}
+	return ""
+}
+
+// objectsOf returns the objects changed by the statements of the file. Objects
+// that were added or modified are evaluated in their final state, as described by
+// the To realm, and objects that no longer exist at the end of the file are skipped.
+func objectsOf(f *sqlcheck.File) []*object {
+	var objs []*object
+	for _, sc := range f.Changes {
+		if sc.Stmt == nil {
+			continue
+		}
+		add := func(action string, o *object) {
+			o.pos, o.action = sc.Stmt.Pos, action
+			if action != "drop" && !final(f.To, o) {
+				return
+			}
+			objs = append(objs, o)
+		}
+		for _, c := range sc.Changes {
+			switch c := c.(type) {
+			case *schema.AddTable:
+				add("add", tableObject(c.T))
+				for _, o := range tableObjects(c.T) {
+					add("add", o)
+				}
+			case *schema.DropTable:
+				add("drop", tableObject(c.T))
+			case *schema.RenameTable:
+				add("rename", tableObject(c.To))
+			case *schema.ModifyTable:
+				add("modify", tableObject(c.T))
+				for _, mc := range c.Changes {
+					switch mc := mc.(type) {
+					case *schema.AddColumn:
+						add("add", columnObject(c.T, mc.C))
+					case *schema.DropColumn:
+						add("drop", columnObject(c.T, mc.C))
+					case *schema.ModifyColumn:
+						add("modify", columnObject(c.T, mc.To))
+					case *schema.RenameColumn:
+						add("rename", columnObject(c.T, mc.To))
+					case *schema.AddIndex:
+						add("add", indexObject(c.T, mc.I))
+					case *schema.DropIndex:
+						add("drop", indexObject(c.T, mc.I))
+					case *schema.ModifyIndex:
+						add("modify", indexObject(c.T, mc.To))
+					case *schema.RenameIndex:
+						add("rename", indexObject(c.T, mc.To))
+					case *schema.AddForeignKey:
+						add("add", fkObject(c.T, mc.F))
+					case *schema.DropForeignKey:
+						add("drop", fkObject(c.T, mc.F))
+					case *schema.ModifyForeignKey:
+						add("modify", fkObject(c.T, mc.To))
+					case *schema.AddCheck:
+						add("add", checkObject(c.T, mc.C))
+					case *schema.DropCheck:
+						add("drop", checkObject(c.T, mc.C))
+					case *schema.ModifyCheck:
+						add("modify", checkObject(c.T, mc.To))
+					}
+				}
+			}
+		}
+	}
+	return objs
+}
+
+func tableObject(t *schema.Table) *object {
+	return &object{kind: objectTable, name: t.Name, table: t, attrs: t.Attrs}
+}
+
+func columnObject(t *schema.Table, c *schema.Column) *object {
+	return &object{kind: objectColumn, name: c.Name, table: t, column: c, attrs: c.Attrs}
+}
+
+func indexObject(t *schema.Table, idx *schema.Index) *object {
+	return &object{kind: objectIndex, name: idx.Name, table: t, index: idx, attrs: idx.Attrs}
+}
+
+func fkObject(t *schema.Table, fk *schema.ForeignKey) *object {
+	return &object{kind: objectForeignKey, name: fk.Symbol, table: t}
+}
+
+func checkObject(t *schema.Table, c *schema.Check) *object {
+	return &object{kind: objectCheck, name: c.Name, table: t, attrs: c.Attrs}
+}
+
+// tableObjects returns the columns, indexes, foreign keys and checks of a table.
+func tableObjects(t *schema.Table) []*object {
+	var objs []*object
+	for _, c := range t.Columns {
+		objs = append(objs, columnObject(t, c))
+	}
+	for _, idx := range t.Indexes {
+		objs = append(objs, indexObject(t, idx))
+	}
+	for _, fk := range t.ForeignKeys {
+		objs = append(objs, fkObject(t, fk))
+	}
+	for _, a := range t.Attrs {
+		if c, ok := a.(*schema.Check); ok {
+			objs = append(objs, checkObject(t, c))
+		}
+	}
+	return objs
+}
+
+// final updates the object to its state in the given realm, and reports
+// if it exists there. A nil realm keeps the object state as is.
+func final(r *schema.Realm, o *object) bool {
+	if r == nil {
+		return true
+	}
+	var (
+		s  *schema.Schema
+		ok bool
+	)
+	switch {
+	case o.table.Schema != nil:
+		s, ok = r.Schema(o.table.Schema.Name)
+	case len(r.Schemas) == 1:
+		s, ok = r.Schemas[0], true
+	}
+	if !ok {
+		// Schema is unknown.
+		return true
+	}
+	t, ok := s.Table(o.table.Name)
+	if !ok {
+		return false
+	}
+	switch o.table = t; o.kind {
+	case objectTable:
+		o.attrs = t.Attrs
+	case objectColumn:
+		c, ok := t.Column(o.name)
+		if !ok {
+			return false
+		}
+		o.column, o.attrs = c, c.Attrs
+	case objectIndex:
+		idx, ok := t.Index(o.name)
+		if !ok {
+			return false
+		}
+		o.index, o.attrs = idx, idx.Attrs
+	case objectForeignKey:
+		if _, ok := t.ForeignKey(o.name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchChange reports if the object change matches one of the given kinds.
+func matchChange(kinds []string, o *object) bool {
+	for _, k := range kinds {
+		if k == o.action || k == o.action+"_"+o.kind {
+			return true
+		}
+	}
+	return false
+}
+
+// matchType reports if the column type matches the pattern. Type arguments are
+// ignored if the pattern does not contain them. For example, "varchar" matches
+// "varchar(255)".
+func matchType(pattern, typ string) bool {
+	if glob(pattern, typ) {
+		return true
+	}
+	if i := strings.IndexByte(typ, '('); i > 0 && !strings.Contains(pattern, "(") {
+		return glob(pattern, strings.TrimSpace(typ[:i]))
+	}
+	return false
+}
+
+// uniqueColumn reports if the column is covered by a single-column unique index.
+func uniqueColumn(t *schema.Table, c *schema.Column) bool {
+	idxs := t.Indexes
+	if t.PrimaryKey != nil {
+		idxs = append([]*schema.Index{t.PrimaryKey}, idxs...)
+	}
+	for _, idx := range idxs {
+		if (idx.Unique || idx == t.PrimaryKey) && len(idx.Parts) == 1 && idx.Parts[0].C != nil && idx.Parts[0].C.Name == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeString returns the driver-specific representation of the given type.
+func typeString(p *sqlcheck.Pass, t *schema.ColumnType) string {
+	if p.Dev != nil {
+		if f, ok := p.Dev.Driver.(interface {
+			FormatType(schema.Type) (string, error)
+		}); ok {
+			if s, err := f.FormatType(t.Type); err == nil {
+				return s
+			}
+		}
+	}
+	if t.Raw != "" {
+		return t.Raw
+	}
+	switch t := t.Type.(type) {
+	case *schema.StringType:
+		return t.T
+	case *schema.IntegerType:
+		return t.T
+	case *schema.DecimalType:
+		return t.T
+	case *schema.FloatType:
+		return t.T
+	case *schema.TimeType:
+		return t.T
+	case *schema.BoolType:
+		return t.T
+	case *schema.BinaryType:
+		return t.T
+	case *schema.JSONType:
+		return t.T
+	default:
+		return ""
+	}
+}
+
+// glob reports if the name matches the lowercase pattern, case-insensitively.
+func glob(pattern, name string) bool {
+	ok, _ := path.Match(pattern, strings.ToLower(name))
+	return ok
+}
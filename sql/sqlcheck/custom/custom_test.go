@@ -0,0 +1,167 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package custom_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/custom"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_Rules(t *testing.T) {
+	var (
+		orders = schema.NewTable("orders").
+			SetSchema(schema.New("test")).
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewFloatColumn("price", "float"),
+				schema.NewDecimalColumn("total_price", "decimal"),
+			)
+		discount = schema.NewNullFloatColumn("discount_price", "float")
+		comment  = schema.NewNullStringColumn("comment", "varchar(255)")
+		to       = schema.NewRealm(schema.New("test").AddTables(
+			schema.NewTable("orders").AddColumns(orders.Columns[0], orders.Columns[1], orders.Columns[2], comment),
+		))
+		reports []sqlcheck.Report
+		pass    = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: &sqlcheck.File{
+				File: migrate.NewLocalFile("1.sql", nil),
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt:    &migrate.Stmt{Pos: 0, Text: "CREATE TABLE orders"},
+						Changes: schema.Changes{&schema.AddTable{T: orders}},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 10, Text: "ALTER TABLE orders ADD COLUMN discount_price"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{T: orders, Changes: schema.Changes{&schema.AddColumn{C: discount}}},
+						},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 20, Text: "ALTER TABLE orders DROP COLUMN discount_price"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{T: orders, Changes: schema.Changes{&schema.DropColumn{C: discount}}},
+						},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 30, Text: "ALTER TABLE orders ADD COLUMN comment"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{T: orders, Changes: schema.Changes{&schema.AddColumn{C: comment}}},
+						},
+					},
+				},
+				To: to,
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				reports = append(reports, r)
+			}),
+		}
+	)
+	az, err := custom.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "rule",
+				Name: "no_float_money",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.StringAttr("description", "Monetary columns must not use floating-point types"),
+					schemahcl.StringAttr("severity", "error"),
+				},
+				Children: []*schemahcl.Resource{
+					{
+						Type: "match",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringAttr("change", "add_column"),
+							schemahcl.StringAttr("type", "float"),
+							schemahcl.StringAttr("name", "*price*"),
+						},
+					},
+				},
+			},
+			{
+				Type: "rule",
+				Name: "nullable_columns",
+				Children: []*schemahcl.Resource{
+					{
+						Type: "match",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringsAttr("change", "add", "modify"),
+							schemahcl.StringAttr("object", "column"),
+							schemahcl.StringAttr("type", "VARCHAR"),
+							schemahcl.BoolAttr("null", true),
+						},
+					},
+					{
+						Type: "match",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringAttr("change", "drop"),
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.EqualError(t, az.Analyze(context.Background(), pass), "Monetary columns must not use floating-point types")
+	require.Len(t, reports, 2)
+
+	// Columns that were dropped in the file are not reported as added.
+	require.Equal(t, "Monetary columns must not use floating-point types", reports[0].Text)
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "no_float_money", Pos: 0, Text: `Column "price" on table "orders" violates rule "no_float_money"`},
+	}, reports[0].Diagnostics)
+
+	require.Equal(t, `rule "nullable_columns" violated`, reports[1].Text)
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "nullable_columns", Pos: 20, Text: `Column "discount_price" on table "orders" violates rule "nullable_columns"`},
+		{Code: "nullable_columns", Pos: 30, Text: `Column "comment" on table "orders" violates rule "nullable_columns"`},
+	}, reports[1].Diagnostics)
+}
+
+func TestNew(t *testing.T) {
+	az, err := custom.New(nil)
+	require.NoError(t, err)
+	require.Empty(t, az.Rules)
+
+	_, err = custom.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "rule", Name: "r"},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: parsing rule "r": missing match block`)
+
+	_, err = custom.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type:     "rule",
+				Name:     "r",
+				Attrs:    []*schemahcl.Attr{schemahcl.StringAttr("severity", "fatal")},
+				Children: []*schemahcl.Resource{{Type: "match"}},
+			},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: parsing rule "r": unknown severity "fatal", expect "error" or "warning"`)
+
+	_, err = custom.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "rule",
+				Name: "r",
+				Children: []*schemahcl.Resource{
+					{Type: "match", Attrs: []*schemahcl.Attr{schemahcl.StringAttr("object", "view")}},
+				},
+			},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: parsing rule "r": unknown match object "view"`)
+}
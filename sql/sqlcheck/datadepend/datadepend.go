@@ -100,6 +100,7 @@ func (a *Analyzer) Diagnostics(_ context.Context, p *sqlcheck.Pass) (diags []sql
 			if !ok {
 				continue
 			}
+			start := len(diags)
 			for _, c := range m.Changes {
 				switch c := c.(type) {
 				case *schema.AddIndex:
@@ -202,6 +203,9 @@ func (a *Analyzer) Diagnostics(_ context.Context, p *sqlcheck.Pass) (diags []sql
 					}
 				}
 			}
+			for i := start; i < len(diags); i++ {
+				p.Stats.Annotate(&diags[i], m.T)
+			}
 		}
 	}
 	return
@@ -0,0 +1,339 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package dataloss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// Analyzer checks for column type changes that reduce the capacity
+	// of a column and might truncate the values it already stores.
+	Analyzer struct {
+		sqlcheck.Options
+		Handler
+
+		// CountRows indicates if the analyzer should count the existing
+		// rows in the dev database that do not fit the new column type.
+		CountRows bool
+	}
+
+	// Handler holds the underlying driver handlers. A nil handler indicates
+	// the driver does not enforce the capacity of the corresponding types.
+	Handler struct {
+		// StringSize returns the maximum number of characters a column of
+		// the given type can hold, or -1 in case it is unbounded.
+		StringSize func(*schema.StringType) int64
+
+		// IntegerSize returns the storage size in bytes of the
+		// given integer type, or 0 in case it is unknown.
+		IntegerSize func(*schema.IntegerType) int
+
+		// DecimalSize returns the effective precision and scale of the given
+		// decimal type. A precision of -1 indicates the type is unbounded.
+		DecimalSize func(*schema.DecimalType) (int, int)
+
+		// TimePrecision returns the fractional seconds precision of the given
+		// time type, or -1 in case the type does not hold the time of day.
+		TimePrecision func(*schema.TimeType) int
+
+		// ModifyType is an optional handler applied on column type
+		// changes that are not covered by the common analysis.
+		ModifyType ColumnHandler
+	}
+
+	// ColumnPass wraps the information needed
+	// by the handler below to diagnose columns.
+	ColumnPass struct {
+		*sqlcheck.Pass
+		Change *sqlcheck.Change     // Change context (statement).
+		Table  *schema.Table        // The table this column belongs to.
+		Modify *schema.ModifyColumn // The diagnosed column change.
+	}
+
+	// ColumnHandler allows provide custom diagnostic for specific column rules.
+	ColumnHandler func(*ColumnPass) ([]sqlcheck.Diagnostic, error)
+)
+
+// New creates a new data-loss Analyzer with the given options.
+func New(r *schemahcl.Resource, h Handler) (*Analyzer, error) {
+	az := &Analyzer{Handler: h}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing data_loss check options: %w", err)
+		}
+		if a, ok := az.Remain().Attr("count_rows"); ok {
+			b, err := a.Bool()
+			if err != nil {
+				return nil, fmt.Errorf("sql/sqlcheck: parsing data_loss count_rows option: %w", err)
+			}
+			az.CountRows = b
+		}
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeNarrowString  = sqlcheck.Code("DL101")
+	codeNarrowInteger = sqlcheck.Code("DL102")
+	codeNarrowDecimal = sqlcheck.Code("DL103")
+	codeNarrowTime    = sqlcheck.Code("DL104")
+	codeDropEnumValue = sqlcheck.Code("DL105")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "data_loss"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(ctx context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			m, ok := c.(*schema.ModifyTable)
+			if !ok || p.File.TableSpan(m.T)&sqlcheck.SpanAdded != 0 {
+				continue
+			}
+			for _, c := range m.Changes {
+				mc, ok := c.(*schema.ModifyColumn)
+				// Columns that were added in this file do not hold values yet.
+				if !ok || !mc.Change.Is(schema.ChangeType) || p.File.ColumnSpan(m.T, mc.From)&sqlcheck.SpanAdded != 0 {
+					continue
+				}
+				cp := &ColumnPass{Pass: p, Change: sc, Table: m.T, Modify: mc}
+				start := len(diags)
+				if d, ok := a.narrowed(cp); ok {
+					if n, ok := a.count(ctx, cp, d.pred); ok {
+						d.Text += affected(n)
+					}
+					diags = append(diags, d.Diagnostic)
+				}
+				if a.ModifyType != nil {
+					d, err := a.ModifyType(cp)
+					if err != nil {
+						return err
+					}
+					diags = append(diags, d...)
+				}
+				for i := start; i < len(diags); i++ {
+					p.Stats.Annotate(&diags[i], m.T)
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "data loss changes detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+type (
+	// narrowing describes a type change that reduces the capacity of a column.
+	narrowing struct {
+		sqlcheck.Diagnostic
+		// pred returns the predicate that matches the rows that do not fit the new
+		// type, given the quoted column name. A nil predicate indicates that
+		// counting is not supported.
+		pred func(string) string
+	}
+)
+
+// narrowed reports if the column change reduces the capacity of the column.
+func (a *Analyzer) narrowed(p *ColumnPass) (*narrowing, bool) {
+	var (
+		d        narrowing
+		from, to = p.Modify.From, p.Modify.To
+	)
+	switch ft := from.Type.Type.(type) {
+	case *schema.StringType:
+		tt, ok := to.Type.Type.(*schema.StringType)
+		if !ok || a.StringSize == nil {
+			return nil, false
+		}
+		fs, ts := a.StringSize(ft), a.StringSize(tt)
+		if ts == -1 || fs != -1 && ts >= fs {
+			return nil, false
+		}
+		d.Code = codeNarrowString
+		d.Text = fmt.Sprintf("Reducing the length of column %q on table %q from %s to %s might truncate existing values", to.Name, p.Table.Name, typeString(p.Pass, ft), typeString(p.Pass, tt))
+		d.pred = func(c string) string {
+			return fmt.Sprintf("CHAR_LENGTH(%s) > %d", c, ts)
+		}
+	case *schema.IntegerType:
+		tt, ok := to.Type.Type.(*schema.IntegerType)
+		if !ok || a.IntegerSize == nil {
+			return nil, false
+		}
+		fs, ts := a.IntegerSize(ft), a.IntegerSize(tt)
+		if fs == 0 || ts == 0 || ts >= fs && (ft.Unsigned == tt.Unsigned || ft.Unsigned && ts > fs) {
+			return nil, false
+		}
+		d.Code = codeNarrowInteger
+		d.Text = fmt.Sprintf("Reducing the range of column %q on table %q from %s to %s might truncate existing values", to.Name, p.Table.Name, typeString(p.Pass, ft), typeString(p.Pass, tt))
+		d.pred = func(c string) string {
+			lo, hi := intRange(ts, tt.Unsigned)
+			return fmt.Sprintf("%s < %s OR %s > %s", c, lo, c, hi)
+		}
+	case *schema.DecimalType:
+		tt, ok := to.Type.Type.(*schema.DecimalType)
+		if !ok || a.DecimalSize == nil {
+			return nil, false
+		}
+		fp, fs := a.DecimalSize(ft)
+		tp, ts := a.DecimalSize(tt)
+		if tp == -1 || fp != -1 && ts >= fs && tp-ts >= fp-fs {
+			return nil, false
+		}
+		d.Code = codeNarrowDecimal
+		d.Text = fmt.Sprintf("Reducing the precision of column %q on table %q from %s to %s might round or truncate existing values", to.Name, p.Table.Name, typeString(p.Pass, ft), typeString(p.Pass, tt))
+		d.pred = func(c string) string {
+			return fmt.Sprintf("ABS(%s) >= 1%s OR %s <> ROUND(%s, %d)", c, strings.Repeat("0", tp-ts), c, c, ts)
+		}
+	case *schema.TimeType:
+		tt, ok := to.Type.Type.(*schema.TimeType)
+		if !ok || a.TimePrecision == nil {
+			return nil, false
+		}
+		switch fp, tp := a.TimePrecision(ft), a.TimePrecision(tt); {
+		case fp != -1 && tp == -1:
+			d.Text = fmt.Sprintf("Changing column %q on table %q from %s to %s drops the time of day of existing values", to.Name, p.Table.Name, typeString(p.Pass, ft), typeString(p.Pass, tt))
+		case tp < fp:
+			d.Text = fmt.Sprintf("Reducing the fractional seconds precision of column %q on table %q from %s to %s might round existing values", to.Name, p.Table.Name, typeString(p.Pass, ft), typeString(p.Pass, tt))
+		default:
+			return nil, false
+		}
+		d.Code = codeNarrowTime
+	case *schema.EnumType:
+		tt, ok := to.Type.Type.(*schema.EnumType)
+		if !ok {
+			return nil, false
+		}
+		var dropped []string
+		for _, v := range ft.Values {
+			if !slices.Contains(tt.Values, v) {
+				dropped = append(dropped, strconv.Quote(v))
+			}
+		}
+		if len(dropped) == 0 {
+			return nil, false
+		}
+		s := "value " + dropped[0]
+		if len(dropped) > 1 {
+			s = fmt.Sprintf("values %s and %s", strings.Join(dropped[:len(dropped)-1], ", "), dropped[len(dropped)-1])
+		}
+		d.Code = codeDropEnumValue
+		d.Text = fmt.Sprintf("Removing enum %s from column %q on table %q might fail or truncate existing values", s, to.Name, p.Table.Name)
+		d.pred = func(c string) string {
+			var kept []string
+			for _, v := range ft.Values {
+				if slices.Contains(tt.Values, v) {
+					kept = append(kept, "'"+strings.ReplaceAll(v, "'", "''")+"'")
+				}
+			}
+			if len(kept) == 0 {
+				return c + " IS NOT NULL"
+			}
+			return fmt.Sprintf("%s NOT IN (%s)", c, strings.Join(kept, ", "))
+		}
+	default:
+		return nil, false
+	}
+	d.Pos = p.Change.Stmt.Pos
+	return &d, true
+}
+
+// count returns the number of rows in the dev database that match the given
+// predicate. The dev database usually does not hold the data of the target
+// database, and errors (e.g., a table that does not exist) are ignored.
+func (a *Analyzer) count(ctx context.Context, p *ColumnPass, pred func(string) string) (int64, bool) {
+	if !a.CountRows || pred == nil || p.Dev == nil {
+		return 0, false
+	}
+	sb, ok := p.Dev.Driver.(interface {
+		StmtBuilder(migrate.PlanOptions) *sqlx.Builder
+	})
+	if !ok {
+		return 0, false
+	}
+	var (
+		c = sb.StmtBuilder(migrate.PlanOptions{}).Ident(p.Modify.To.Name).String()
+		b = sb.StmtBuilder(migrate.PlanOptions{}).P("SELECT COUNT(*) FROM").Table(p.Table).P("WHERE", pred(c))
+	)
+	rows, err := p.Dev.QueryContext(ctx, b.String())
+	if err != nil {
+		return 0, false
+	}
+	var n int64
+	if err := sqlx.ScanOne(rows, &n); err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// affected returns the diagnostic suffix that describes the number of affected rows.
+func affected(n int64) string {
+	if n == 1 {
+		return " (1 existing row does not fit the new type)"
+	}
+	return fmt.Sprintf(" (%d existing rows do not fit the new type)", n)
+}
+
+// intRange returns the range of values an integer type of the given size can hold.
+func intRange(size int, unsigned bool) (string, string) {
+	bits := uint(size) * 8
+	switch {
+	case unsigned && bits >= 64:
+		return "0", strconv.FormatUint(math.MaxUint64, 10)
+	case unsigned:
+		return "0", strconv.FormatUint(1<<bits-1, 10)
+	case bits >= 64:
+		return strconv.FormatInt(math.MinInt64, 10), strconv.FormatInt(math.MaxInt64, 10)
+	default:
+		return strconv.FormatInt(-1<<(bits-1), 10), strconv.FormatInt(1<<(bits-1)-1, 10)
+	}
+}
+
+// typeString returns the driver-specific representation of the given type.
+func typeString(p *sqlcheck.Pass, t schema.Type) string {
+	if p.Dev != nil {
+		if f, ok := p.Dev.Driver.(interface {
+			FormatType(schema.Type) (string, error)
+		}); ok {
+			if s, err := f.FormatType(t); err == nil {
+				return s
+			}
+		}
+	}
+	switch t := t.(type) {
+	case *schema.StringType:
+		return t.T
+	case *schema.IntegerType:
+		return t.T
+	case *schema.DecimalType:
+		return t.T
+	case *schema.TimeType:
+		return t.T
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
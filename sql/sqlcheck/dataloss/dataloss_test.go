@@ -0,0 +1,176 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package dataloss_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/dataloss"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_ModifyColumn(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users").SetSchema(schema.New("test"))
+		modify = func(from, to schema.Type) *sqlcheck.Change {
+			return &sqlcheck.Change{
+				Stmt: &migrate.Stmt{Text: "ALTER TABLE users"},
+				Changes: schema.Changes{
+					&schema.ModifyTable{
+						T: users,
+						Changes: schema.Changes{
+							&schema.ModifyColumn{
+								From:   schema.NewColumn("c").SetType(from),
+								To:     schema.NewColumn("c").SetType(to),
+								Change: schema.ChangeType,
+							},
+						},
+					},
+				},
+			}
+		}
+		pass = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					modify(&schema.StringType{T: "varchar", Size: 255}, &schema.StringType{T: "varchar", Size: 64}),
+					modify(&schema.StringType{T: "varchar", Size: 64}, &schema.StringType{T: "varchar", Size: 255}),
+					modify(&schema.StringType{T: "text"}, &schema.StringType{T: "varchar", Size: 255}),
+					modify(&schema.IntegerType{T: "bigint"}, &schema.IntegerType{T: "int"}),
+					modify(&schema.IntegerType{T: "int"}, &schema.IntegerType{T: "int", Unsigned: true}),
+					modify(&schema.IntegerType{T: "int", Unsigned: true}, &schema.IntegerType{T: "bigint"}),
+					modify(&schema.DecimalType{T: "decimal", Precision: 10, Scale: 4}, &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}),
+					modify(&schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, &schema.DecimalType{T: "decimal", Precision: 12, Scale: 2}),
+					modify(&schema.TimeType{T: "timestamp", Precision: func() *int { p := 6; return &p }()}, &schema.TimeType{T: "timestamp"}),
+					modify(&schema.TimeType{T: "timestamp"}, &schema.TimeType{T: "date"}),
+					modify(&schema.EnumType{T: "enum", Values: []string{"a", "b", "c"}}, &schema.EnumType{T: "enum", Values: []string{"a"}}),
+					modify(&schema.EnumType{T: "enum", Values: []string{"a"}}, &schema.EnumType{T: "enum", Values: []string{"a", "b"}}),
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	az, err := dataloss.New(nil, dataloss.Handler{
+		StringSize: func(t *schema.StringType) int64 {
+			if t.T == "text" {
+				return 1<<16 - 1
+			}
+			return int64(t.Size)
+		},
+		IntegerSize: func(t *schema.IntegerType) int {
+			if t.T == "bigint" {
+				return 8
+			}
+			return 4
+		},
+		DecimalSize: func(t *schema.DecimalType) (int, int) {
+			return t.Precision, t.Scale
+		},
+		TimePrecision: func(t *schema.TimeType) int {
+			switch {
+			case t.T == "date":
+				return -1
+			case t.Precision != nil:
+				return *t.Precision
+			}
+			return 0
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, "data loss changes detected", report.Text)
+	require.Len(t, report.Diagnostics, 8)
+	for i, d := range []struct{ code, text string }{
+		{"DL101", `Reducing the length of column "c" on table "users" from varchar to varchar might truncate existing values`},
+		{"DL101", `Reducing the length of column "c" on table "users" from text to varchar might truncate existing values`},
+		{"DL102", `Reducing the range of column "c" on table "users" from bigint to int might truncate existing values`},
+		{"DL102", `Reducing the range of column "c" on table "users" from int to int might truncate existing values`},
+		{"DL103", `Reducing the precision of column "c" on table "users" from decimal to decimal might round or truncate existing values`},
+		{"DL104", `Reducing the fractional seconds precision of column "c" on table "users" from timestamp to timestamp might round existing values`},
+		{"DL104", `Changing column "c" on table "users" from timestamp to date drops the time of day of existing values`},
+		{"DL105", `Removing enum values "b" and "c" from column "c" on table "users" might fail or truncate existing values`},
+	} {
+		require.Equal(t, d.code, report.Diagnostics[i].Code)
+		require.Equal(t, d.text, report.Diagnostics[i].Text)
+	}
+
+	// Enum values are checked without driver handlers.
+	az, err = dataloss.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "data_loss",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("error", true),
+				},
+			},
+		},
+	}, dataloss.Handler{})
+	require.NoError(t, err)
+	require.EqualError(t, az.Analyze(context.Background(), pass), "data loss changes detected")
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "DL105", report.Diagnostics[0].Code)
+	require.Equal(t, `Removing enum values "b" and "c" from column "c" on table "users" might fail or truncate existing values`, report.Diagnostics[0].Text)
+}
+
+func TestAnalyzer_SkipAddedTable(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users").SetSchema(schema.New("test"))
+		pass   = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt:    &migrate.Stmt{Text: "CREATE TABLE users"},
+						Changes: schema.Changes{&schema.AddTable{T: users}},
+					},
+					{
+						Stmt: &migrate.Stmt{Text: "ALTER TABLE users"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.ModifyColumn{
+										From:   schema.NewEnumColumn("c", schema.EnumValues("a", "b")),
+										To:     schema.NewEnumColumn("c", schema.EnumValues("a")),
+										Change: schema.ChangeType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	az, err := dataloss.New(nil, dataloss.Handler{})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Nil(t, report)
+}
+
+type testFile struct {
+	name string
+	migrate.File
+}
+
+func (t testFile) Name() string {
+	return t.name
+}
@@ -75,7 +75,7 @@ func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 				}
 			case *schema.DropTable:
 				if p.File.SchemaSpan(c.T.Schema) != sqlcheck.SpanDropped && p.File.TableSpan(c.T) != sqlcheck.SpanTemporary && !a.hasEmptyTableCheck(p, c.T) {
-					diags = append(diags, sqlcheck.Diagnostic{
+					d := sqlcheck.Diagnostic{
 						Code: codeDropT,
 						Pos:  sc.Stmt.Pos,
 						Text: fmt.Sprintf("Dropping table %q", c.T.Name),
@@ -84,7 +84,9 @@ func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 								Message: fmt.Sprintf("Add a pre-migration check to ensure table %q is empty before dropping it", c.T.Name),
 							},
 						},
-					})
+					}
+					p.Stats.Annotate(&d, c.T)
+					diags = append(diags, d)
 					if stmt, err := a.emptyTableCheckStmt(p, c.T); err == nil {
 						edits = append(edits, stmt)
 					}
@@ -103,6 +105,7 @@ func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 						}
 					}
 				}
+				start := len(diags)
 				switch n := len(names); {
 				case n == 1:
 					diags = append(diags, sqlcheck.Diagnostic{
@@ -128,6 +131,9 @@ func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 						},
 					})
 				}
+				for i := start; i < len(diags); i++ {
+					p.Stats.Annotate(&diags[i], c.T)
+				}
 			}
 		}
 	}
@@ -74,6 +74,21 @@ func TestAnalyzer_DropTable(t *testing.T) {
 	require.Len(t, report.Diagnostics, 2)
 	require.Equal(t, `Dropping table "users"`, report.Diagnostics[0].Text)
 	require.Equal(t, `Dropping table "posts"`, report.Diagnostics[1].Text)
+
+	// Table statistics of the target database.
+	pass.Stats = &sqlcheck.Stats{
+		Tables: map[string]sqlcheck.TableStats{
+			"test.users": {Rows: 2_000_000, Bytes: 3 << 29},
+			"test.posts": {Rows: 10},
+		},
+		LargeRows: 1_000_000,
+	}
+	err = az.Analyze(context.Background(), pass)
+	require.Error(t, err)
+	require.Equal(t, `Dropping table "users" (~2000000 rows, 1.5 GiB)`, report.Diagnostics[0].Text)
+	require.Equal(t, sqlcheck.SeverityError, report.Diagnostics[0].Severity)
+	require.Equal(t, `Dropping table "posts" (~10 rows)`, report.Diagnostics[1].Text)
+	require.Empty(t, report.Diagnostics[1].Severity)
 }
 
 func TestAnalyzer_SkipTemporaryTable(t *testing.T) {
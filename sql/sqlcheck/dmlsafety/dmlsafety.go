@@ -0,0 +1,161 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package dmlsafety implements an analyzer for data-modifying statements in
+// migration files, such as UPDATE or DELETE statements without a WHERE clause.
+package dmlsafety
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// Analyzer checks data-modifying statements that affect all rows of a table.
+	// For example:
+	//
+	//	lint {
+	//	  dml_safety {
+	//	    error         = true
+	//	    backfill_rows = 1000000
+	//	  }
+	//	}
+	Analyzer struct {
+		sqlcheck.Options
+
+		// Dialect holds the lexical rules used for parsing the statements.
+		Dialect Dialect
+
+		// BackfillRows is the minimum estimated number of rows of a source table
+		// to report unbounded backfills from it. Row estimates are read only from
+		// the statistics of the target database (see the --stats-url flag), as the
+		// dev database holds no data. Backfills from tables without an estimate are
+		// not reported.
+		BackfillRows int64
+	}
+)
+
+// DefaultBackfillRows is the default value of the backfill_rows option.
+const DefaultBackfillRows = 100_000
+
+// New creates a new DML safety Analyzer with the given options,
+// that parses statements according to the given dialect.
+func New(r *schemahcl.Resource, d Dialect) (*Analyzer, error) {
+	az := &Analyzer{Dialect: d, BackfillRows: DefaultBackfillRows}
+	r, ok := r.Resource(az.Name())
+	if !ok {
+		return az, nil
+	}
+	if err := r.As(&az.Options); err != nil {
+		return nil, fmt.Errorf("sql/sqlcheck: parsing dml_safety check options: %w", err)
+	}
+	if a, ok := az.Remain().Attr("backfill_rows"); ok {
+		n, err := a.Int()
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing dml_safety backfill_rows option: %w", err)
+		}
+		az.BackfillRows = int64(n)
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeUpdateAll = sqlcheck.Code("DM101")
+	codeDeleteAll = sqlcheck.Code("DM102")
+	codeTruncate  = sqlcheck.Code("DM103")
+	codeBackfill  = sqlcheck.Code("DM104")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "dml_safety"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	stmts, err := a.stmts(p)
+	if err != nil {
+		return err
+	}
+	var diags []sqlcheck.Diagnostic
+	for _, s := range stmts {
+		dml, ok := a.Dialect.Parse(s.Text)
+		if !ok {
+			continue
+		}
+		var (
+			code, text string
+			rows       int64
+		)
+		switch {
+		case dml.Kind == KindUpdate && !dml.Where:
+			code, text = codeUpdateAll, fmt.Sprintf("UPDATE on table %q without a WHERE clause modifies all rows", dml.Table)
+			rows = rowCount(p, dml.Table)
+		case dml.Kind == KindDelete && !dml.Where:
+			code, text = codeDeleteAll, fmt.Sprintf("DELETE on table %q without a WHERE clause removes all rows", dml.Table)
+			rows = rowCount(p, dml.Table)
+		case dml.Kind == KindTruncate:
+			code, text = codeTruncate, fmt.Sprintf("TRUNCATE of table %q removes all rows", dml.Table)
+			rows = rowCount(p, dml.Table)
+		case dml.Kind == KindInsert && dml.Source != "" && !dml.Bounded():
+			if rows = rowCount(p, dml.Source); rows <= 0 || rows < a.BackfillRows {
+				continue
+			}
+			code, text = codeBackfill, fmt.Sprintf("Unbounded backfill of table %q from table %q without a WHERE or LIMIT clause", dml.Table, dml.Source)
+		default:
+			continue
+		}
+		if rows > 0 {
+			text += fmt.Sprintf(" (~%d rows)", rows)
+		}
+		diags = append(diags, sqlcheck.Diagnostic{Code: code, Pos: s.Pos, Text: text})
+	}
+	if len(diags) > 0 {
+		const reportText = "unsafe data changes detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// stmts returns the statements of the file. In case the file was
+// analyzed as a whole, its statements are scanned from its content.
+func (*Analyzer) stmts(p *sqlcheck.Pass) ([]*migrate.Stmt, error) {
+	var stmts []*migrate.Stmt
+	for _, c := range p.File.Changes {
+		if c.Stmt == nil {
+			continue
+		}
+		if c.Stmt.Text == "" {
+			return p.File.StmtDecls()
+		}
+		stmts = append(stmts, c.Stmt)
+	}
+	return stmts, nil
+}
+
+// rowCount returns the estimated number of rows in the table, as
+// read from the statistics of the target database, or 0 if unknown.
+func rowCount(p *sqlcheck.Pass, name string) int64 {
+	t := &schema.Table{Name: name}
+	if i := strings.LastIndexByte(name, '.'); i != -1 {
+		t.Name, t.Schema = name[i+1:], schema.New(name[:i])
+	}
+	ts, ok := p.Stats.Table(t)
+	if !ok {
+		return 0
+	}
+	return ts.Rows
+}
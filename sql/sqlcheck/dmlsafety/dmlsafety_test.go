@@ -0,0 +1,123 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package dmlsafety_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/dmlsafety"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want *dmlsafety.Stmt
+	}{
+		{text: "CREATE TABLE t(c int)"},
+		{text: "SELECT * FROM t"},
+		{text: "UPDATE t SET c = 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t"}},
+		{text: "update `s`.`t` set c = 'WHERE'", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "s.t"}},
+		{text: "UPDATE t SET c = (SELECT 1 FROM u WHERE u.id = 1)", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t"}},
+		{text: "UPDATE ONLY \"t\" SET c = 1 -- WHERE\nWHERE c IS NULL", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t", Where: true}},
+		{text: "DELETE FROM t", want: &dmlsafety.Stmt{Kind: dmlsafety.KindDelete, Table: "t"}},
+		{text: "DELETE LOW_PRIORITY FROM t /* WHERE */ LIMIT 10", want: &dmlsafety.Stmt{Kind: dmlsafety.KindDelete, Table: "t", Limit: true}},
+		{text: "WITH x AS (SELECT 1 WHERE true) DELETE FROM t", want: &dmlsafety.Stmt{Kind: dmlsafety.KindDelete, Table: "t"}},
+		{text: "TRUNCATE TABLE public.t", want: &dmlsafety.Stmt{Kind: dmlsafety.KindTruncate, Table: "public.t"}},
+		{text: "INSERT INTO t VALUES (1)", want: &dmlsafety.Stmt{Kind: dmlsafety.KindInsert, Table: "t"}},
+		{text: "INSERT INTO t (c) SELECT c FROM u", want: &dmlsafety.Stmt{Kind: dmlsafety.KindInsert, Table: "t", Source: "u"}},
+		{text: "INSERT INTO t SELECT $$WHERE$$ FROM u WHERE c > 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindInsert, Table: "t", Source: "u", Where: true}},
+	} {
+		t.Run(tt.text, func(t *testing.T) {
+			s, ok := dmlsafety.Parse(tt.text)
+			require.Equal(t, tt.want != nil, ok)
+			require.Equal(t, tt.want, s)
+		})
+	}
+
+	// Dialect specific lexical rules.
+	for _, tt := range []struct {
+		dialect dmlsafety.Dialect
+		text    string
+		want    *dmlsafety.Stmt
+	}{
+		{dialect: dmlsafety.Postgres, text: "UPDATE t SET a = a # 1 WHERE id = 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t", Where: true}},
+		{dialect: dmlsafety.Postgres, text: "UPDATE t SET a = 'C:\\' WHERE id = 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t", Where: true}},
+		{dialect: dmlsafety.Postgres, text: "UPDATE t SET a = E'it\\'s' WHERE id = 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t", Where: true}},
+		{dialect: dmlsafety.MySQL, text: "UPDATE t SET a = 1 # WHERE id = 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t"}},
+		{dialect: dmlsafety.MySQL, text: "UPDATE t SET a = 'it\\'s' WHERE id = 1", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t", Where: true}},
+		{dialect: dmlsafety.MySQL, text: "UPDATE t SET a = \"WHERE\"", want: &dmlsafety.Stmt{Kind: dmlsafety.KindUpdate, Table: "t"}},
+	} {
+		t.Run(tt.text, func(t *testing.T) {
+			s, ok := tt.dialect.Parse(tt.text)
+			require.Equal(t, tt.want != nil, ok)
+			require.Equal(t, tt.want, s)
+		})
+	}
+}
+
+func TestAnalyzer_DMLSafety(t *testing.T) {
+	var (
+		reports []sqlcheck.Report
+		pass    = &sqlcheck.Pass{
+			Stats: &sqlcheck.Stats{
+				Tables: map[string]sqlcheck.TableStats{
+					"public.users":  {Rows: 500},
+					"public.events": {Rows: 2_000_000},
+					"public.logs":   {Rows: 10},
+				},
+			},
+			File: &sqlcheck.File{
+				File: migrate.NewLocalFile("1.sql", nil),
+				Changes: []*sqlcheck.Change{
+					{Stmt: &migrate.Stmt{Pos: 0, Text: "UPDATE users SET active = true"}},
+					{Stmt: &migrate.Stmt{Pos: 10, Text: "DELETE FROM users WHERE active = false"}},
+					{Stmt: &migrate.Stmt{Pos: 20, Text: "DELETE FROM sessions"}},
+					{Stmt: &migrate.Stmt{Pos: 30, Text: "TRUNCATE public.logs"}},
+					{Stmt: &migrate.Stmt{Pos: 40, Text: "INSERT INTO archive SELECT * FROM events"}},
+					{Stmt: &migrate.Stmt{Pos: 50, Text: "INSERT INTO archive SELECT * FROM logs"}},
+					{Stmt: &migrate.Stmt{Pos: 60, Text: "INSERT INTO archive SELECT * FROM events LIMIT 10"}},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				reports = append(reports, r)
+			}),
+		}
+	)
+	az, err := dmlsafety.New(&schemahcl.Resource{}, dmlsafety.Postgres)
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, reports, 1)
+	require.Equal(t, "unsafe data changes detected", reports[0].Text)
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "DM101", Pos: 0, Text: `UPDATE on table "users" without a WHERE clause modifies all rows (~500 rows)`},
+		{Code: "DM102", Pos: 20, Text: `DELETE on table "sessions" without a WHERE clause removes all rows`},
+		{Code: "DM103", Pos: 30, Text: `TRUNCATE of table "public.logs" removes all rows (~10 rows)`},
+		{Code: "DM104", Pos: 40, Text: `Unbounded backfill of table "archive" from table "events" without a WHERE or LIMIT clause (~2000000 rows)`},
+	}, reports[0].Diagnostics)
+
+	// Lower threshold, and report as errors.
+	reports = nil
+	az, err = dmlsafety.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "dml_safety",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("error", true),
+					schemahcl.IntAttr("backfill_rows", 5),
+				},
+			},
+		},
+	}, dmlsafety.Postgres)
+	require.NoError(t, err)
+	require.EqualError(t, az.Analyze(context.Background(), pass), "unsafe data changes detected")
+	require.Len(t, reports[0].Diagnostics, 5)
+	require.Equal(t, 50, reports[0].Diagnostics[4].Pos)
+}
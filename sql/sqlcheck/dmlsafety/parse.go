@@ -0,0 +1,259 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package dmlsafety
+
+import "strings"
+
+// Statement kinds.
+const (
+	KindUpdate   = "UPDATE"
+	KindDelete   = "DELETE"
+	KindTruncate = "TRUNCATE"
+	KindInsert   = "INSERT"
+)
+
+// A Stmt describes a data-modifying statement.
+type Stmt struct {
+	Kind   string // Statement kind. For example, UPDATE.
+	Table  string // Target table, optionally qualified with its schema.
+	Source string // Source table of INSERT ... SELECT statements, if exists.
+	Where  bool   // Statement (or its SELECT part) has a WHERE clause.
+	Limit  bool   // Statement (or its SELECT part) has a LIMIT clause.
+}
+
+// A Dialect describes the lexical rules of an SQL dialect that differ from standard SQL.
+type Dialect struct {
+	HashComments    bool // '#' starts a line comment, as in MySQL.
+	BackslashEscape bool // Backslashes escape characters in string literals, as in MySQL.
+	EscapeStrings   bool // Backslashes escape characters in E'...' strings, as in PostgreSQL.
+}
+
+// Dialects of the supported drivers. SQLite follows the standard SQL rules.
+var (
+	MySQL    = Dialect{HashComments: true, BackslashEscape: true}
+	Postgres = Dialect{EscapeStrings: true}
+)
+
+// Parse parses the given statement text using the standard SQL lexical rules.
+// See Dialect.Parse for more info.
+func Parse(text string) (*Stmt, bool) {
+	return Dialect{}.Parse(text)
+}
+
+// Parse parses the given statement text and returns its description in case
+// it is a data-modifying statement. The parser is lexical: it skips comments,
+// string literals and quoted identifiers, and only considers clauses that are
+// not nested in parentheses (e.g., a WHERE clause of a sub-query is ignored).
+func (d Dialect) Parse(text string) (*Stmt, bool) {
+	toks := d.tokenize(text)
+	// Skip common table expressions.
+	if len(toks) > 0 && toks[0].keyword("WITH") {
+		i := 1
+		for i < len(toks) && !(toks[i].depth == 0 && toks[i].oneOf(KindUpdate, KindDelete, KindInsert, "REPLACE")) {
+			i++
+		}
+		toks = toks[i:]
+	}
+	if len(toks) == 0 {
+		return nil, false
+	}
+	var (
+		s    = &Stmt{}
+		rest []token
+	)
+	switch first := toks[0]; {
+	case first.oneOf(KindUpdate):
+		s.Kind = KindUpdate
+		s.Table, rest = tableName(skip(toks[1:], "LOW_PRIORITY", "IGNORE", "ONLY"))
+	case first.oneOf(KindDelete):
+		s.Kind = KindDelete
+		rest = skip(toks[1:], "LOW_PRIORITY", "QUICK", "IGNORE")
+		if len(rest) > 0 && rest[0].keyword("FROM") {
+			rest = skip(rest[1:], "ONLY")
+		}
+		s.Table, rest = tableName(rest)
+	case first.oneOf(KindTruncate):
+		s.Kind = KindTruncate
+		s.Table, rest = tableName(skip(toks[1:], "TABLE", "ONLY"))
+		return s, s.Table != ""
+	case first.oneOf(KindInsert, "REPLACE"):
+		s.Kind = KindInsert
+		s.Table, rest = tableName(skip(toks[1:], "LOW_PRIORITY", "DELAYED", "HIGH_PRIORITY", "IGNORE", "INTO"))
+		i := indexTop(rest, "SELECT")
+		if i == -1 {
+			return s, s.Table != ""
+		}
+		rest = rest[i+1:]
+		if j := indexTop(rest, "FROM"); j != -1 {
+			s.Source, _ = tableName(rest[j+1:])
+		}
+	default:
+		return nil, false
+	}
+	s.Where = indexTop(rest, "WHERE") != -1
+	s.Limit = indexTop(rest, "LIMIT") != -1
+	return s, s.Table != ""
+}
+
+// Bounded reports if the statement is bounded by a WHERE or a LIMIT clause.
+func (s *Stmt) Bounded() bool {
+	return s.Where || s.Limit
+}
+
+// A token of an SQL statement.
+type token struct {
+	text   string // Keyword or identifier as written, or punctuation.
+	word   bool   // Keyword or identifier.
+	quoted bool   // Quoted identifier.
+	depth  int    // Parentheses depth.
+}
+
+func (t token) keyword(k string) bool {
+	return t.word && !t.quoted && strings.EqualFold(t.text, k)
+}
+
+func (t token) oneOf(ks ...string) bool {
+	for _, k := range ks {
+		if t.keyword(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits the statement into words and punctuation tokens. Literals
+// are dropped, and quoted identifiers are returned as (unquoted) words.
+func (d Dialect) tokenize(text string) []token {
+	var (
+		toks  []token
+		depth int
+	)
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(text[i:], "--"), c == '#' && d.HashComments:
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			if j := strings.Index(text[i+2:], "*/"); j != -1 {
+				i += j + 4
+			} else {
+				i = len(text)
+			}
+		case c == '\'':
+			i = skipQuoted(text, i, c, d.BackslashEscape)
+		case (c == 'E' || c == 'e') && d.EscapeStrings && i+1 < len(text) && text[i+1] == '\'' && (i == 0 || !isIdent(text[i-1])):
+			i = skipQuoted(text, i+1, '\'', true)
+		case c == '"' && d.BackslashEscape:
+			// Double-quoted strings, unless the ANSI_QUOTES mode is enabled.
+			i = skipQuoted(text, i, c, true)
+		case c == '"', c == '`', c == '[':
+			q := c
+			if c == '[' {
+				q = ']'
+			}
+			j := skipQuoted(text, i, q, false)
+			id := strings.TrimSuffix(text[i+1:j], string(q))
+			toks = append(toks, token{text: strings.ReplaceAll(id, string([]byte{q, q}), string(q)), word: true, quoted: true, depth: depth})
+			i = j
+		case c == '$' && dollarTag(text[i:]) != "":
+			// Dollar-quoted strings (e.g., $$...$$ or $tag$...$tag$).
+			tag := dollarTag(text[i:])
+			if j := strings.Index(text[i+len(tag):], tag); j != -1 {
+				i += j + 2*len(tag)
+			} else {
+				i = len(text)
+			}
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case isIdent(c) && !isDigit(c):
+			j := i
+			for j < len(text) && (isIdent(text[j]) || text[j] == '$') {
+				j++
+			}
+			toks = append(toks, token{text: text[i:j], word: true, depth: depth})
+			i = j
+		default:
+			toks = append(toks, token{text: string(c), depth: depth})
+			i++
+		}
+	}
+	return toks
+}
+
+// dollarTag returns the dollar-quote tag at the beginning of s, if exists.
+func dollarTag(s string) string {
+	j := 1
+	for j < len(s) && isIdent(s[j]) {
+		j++
+	}
+	if j < len(s) && s[j] == '$' && (j == 1 || !isDigit(s[1])) {
+		return s[:j+1]
+	}
+	return ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdent reports if the byte can be part of an unquoted
+// identifier. Non-ASCII bytes are considered as letters.
+func isIdent(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c == '_' || c >= 0x80
+}
+
+// skipQuoted returns the position after the quoted literal starting at i. Quotes
+// are escaped by doubling them, or with a backslash in case backslash is set.
+func skipQuoted(s string, i int, q byte, backslash bool) int {
+	for i++; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && backslash:
+			i++
+		case s[i] == q && i+1 < len(s) && s[i+1] == q:
+			i++
+		case s[i] == q:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// skip skips the given keywords at the beginning of the tokens.
+func skip(toks []token, ks ...string) []token {
+	for len(toks) > 0 && toks[0].oneOf(ks...) {
+		toks = toks[1:]
+	}
+	return toks
+}
+
+// tableName returns the (optionally qualified) table name at
+// the beginning of the tokens, and the rest of the tokens.
+func tableName(toks []token) (string, []token) {
+	if len(toks) == 0 || !toks[0].word {
+		return "", toks
+	}
+	name, toks := toks[0].text, toks[1:]
+	for len(toks) > 1 && toks[0].text == "." && toks[1].word {
+		name, toks = name+"."+toks[1].text, toks[2:]
+	}
+	return name, toks
+}
+
+// indexTop returns the index of the first top-level keyword, or -1 if not found.
+func indexTop(toks []token, k string) int {
+	for i, t := range toks {
+		if t.depth == 0 && t.keyword(k) {
+			return i
+		}
+	}
+	return -1
+}
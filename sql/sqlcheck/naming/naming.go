@@ -0,0 +1,203 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package naming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// Analyzer checks that the names of added or renamed objects follow
+	// the naming policy configured in the project file. For example:
+	//
+	//	lint {
+	//	  naming {
+	//	    match   = "^[a-z_]+$"
+	//	    message = "must be lowercase"
+	//	    index {
+	//	      match = "^idx_"
+	//	    }
+	//	  }
+	//	}
+	Analyzer struct {
+		sqlcheck.Options
+
+		// Rules for the different object types. A nil
+		// rule indicates the object names are not checked.
+		Table, Column, Index, ForeignKey, Check *Rule
+	}
+
+	// Rule describes a naming rule.
+	Rule struct {
+		// Match is the pattern that names must match.
+		Match *regexp.Regexp
+		// Message is an optional message that is
+		// appended to the reported diagnostics.
+		Message string
+	}
+)
+
+// New creates a new naming Analyzer with the given options.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{}
+	r, ok := r.Resource(az.Name())
+	if !ok {
+		return az, nil
+	}
+	if err := r.As(&az.Options); err != nil {
+		return nil, fmt.Errorf("sql/sqlcheck: parsing naming check options: %w", err)
+	}
+	def, err := ruleFrom(az.Remain(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for n, r := range map[string]**Rule{
+		"table":       &az.Table,
+		"column":      &az.Column,
+		"index":       &az.Index,
+		"foreign_key": &az.ForeignKey,
+		"check":       &az.Check,
+	} {
+		*r = def
+		if c, ok := az.Remain().Resource(n); ok {
+			if *r, err = ruleFrom(c, def); err != nil {
+				return nil, fmt.Errorf("sql/sqlcheck: parsing naming %s options: %w", n, err)
+			}
+		}
+		// Object types without a pattern are not checked.
+		if (*r).Match == nil {
+			*r = nil
+		}
+	}
+	return az, nil
+}
+
+// ruleFrom parses a rule from the given resource. Unset attributes are inherited from the parent rule.
+func ruleFrom(r *schemahcl.Resource, parent *Rule) (*Rule, error) {
+	rule := &Rule{}
+	if parent != nil {
+		*rule = *parent
+	}
+	if a, ok := r.Attr("match"); ok {
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing naming match option: %w", err)
+		}
+		if rule.Match, err = regexp.Compile(s); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: compiling naming match pattern: %w", err)
+		}
+	}
+	if a, ok := r.Attr("message"); ok {
+		s, err := a.String()
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing naming message option: %w", err)
+		}
+		rule.Message = s
+	}
+	return rule, nil
+}
+
+// List of codes.
+var (
+	codeTableName  = sqlcheck.Code("NM101")
+	codeColumnName = sqlcheck.Code("NM102")
+	codeIndexName  = sqlcheck.Code("NM103")
+	codeFKName     = sqlcheck.Code("NM104")
+	codeCheckName  = sqlcheck.Code("NM105")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "naming"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		check := func(r *Rule, code, kind, name string, t *schema.Table) {
+			if r == nil || name == "" || r.Match.MatchString(name) {
+				return
+			}
+			text := fmt.Sprintf("%s named %q", kind, name)
+			if t != nil {
+				text += fmt.Sprintf(" on table %q", t.Name)
+			}
+			text += " violates the naming policy"
+			if r.Message != "" {
+				text += ": " + r.Message
+			}
+			diags = append(diags, sqlcheck.Diagnostic{
+				Code: code,
+				Pos:  sc.Stmt.Pos,
+				Text: text,
+			})
+		}
+		for _, c := range sc.Changes {
+			switch c := c.(type) {
+			case *schema.AddTable:
+				check(a.Table, codeTableName, "Table", c.T.Name, nil)
+				for _, col := range c.T.Columns {
+					check(a.Column, codeColumnName, "Column", col.Name, c.T)
+				}
+				for _, idx := range c.T.Indexes {
+					check(a.Index, codeIndexName, "Index", idx.Name, c.T)
+				}
+				for _, fk := range c.T.ForeignKeys {
+					check(a.ForeignKey, codeFKName, "Foreign key", fk.Symbol, c.T)
+				}
+				for _, at := range c.T.Attrs {
+					if ck, ok := at.(*schema.Check); ok {
+						check(a.Check, codeCheckName, "Check constraint", ck.Name, c.T)
+					}
+				}
+			case *schema.RenameTable:
+				check(a.Table, codeTableName, "Table", c.To.Name, nil)
+			case *schema.ModifyTable:
+				for _, mc := range c.Changes {
+					switch mc := mc.(type) {
+					case *schema.AddColumn:
+						check(a.Column, codeColumnName, "Column", mc.C.Name, c.T)
+					case *schema.RenameColumn:
+						check(a.Column, codeColumnName, "Column", mc.To.Name, c.T)
+					case *schema.AddIndex:
+						check(a.Index, codeIndexName, "Index", mc.I.Name, c.T)
+					case *schema.RenameIndex:
+						check(a.Index, codeIndexName, "Index", mc.To.Name, c.T)
+					case *schema.AddForeignKey:
+						check(a.ForeignKey, codeFKName, "Foreign key", mc.F.Symbol, c.T)
+					case *schema.AddCheck:
+						check(a.Check, codeCheckName, "Check constraint", mc.C.Name, c.T)
+					case *schema.RenameConstraint:
+						switch to := mc.To.(type) {
+						case *schema.ForeignKey:
+							check(a.ForeignKey, codeFKName, "Foreign key", to.Symbol, c.T)
+						case *schema.Check:
+							check(a.Check, codeCheckName, "Check constraint", to.Name, c.T)
+						case *schema.Index:
+							check(a.Index, codeIndexName, "Index", to.Name, c.T)
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "naming violations detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
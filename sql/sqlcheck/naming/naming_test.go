@@ -0,0 +1,158 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package naming_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/naming"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_Names(t *testing.T) {
+	users := schema.NewTable("Users").
+		SetSchema(schema.New("test")).
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewIntColumn("postID", "int"),
+		)
+	users.AddIndexes(schema.NewIndex("users_id").AddColumns(users.Columns[0]))
+	users.AddChecks(schema.NewCheck().SetName("positive_id").SetExpr("id > 0"))
+	posts := schema.NewTable("posts").
+		SetSchema(schema.New("test")).
+		AddColumns(schema.NewIntColumn("id", "int"))
+	var (
+		report *sqlcheck.Report
+		pass   = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt:    &migrate.Stmt{Pos: 0, Text: "CREATE TABLE Users"},
+						Changes: schema.Changes{&schema.AddTable{T: users}},
+					},
+					{
+						Stmt: &migrate.Stmt{Pos: 10, Text: "ALTER TABLE posts"},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: posts,
+								Changes: schema.Changes{
+									&schema.AddColumn{C: schema.NewIntColumn("author_id", "int")},
+									&schema.RenameColumn{From: schema.NewIntColumn("a", "int"), To: schema.NewIntColumn("Title", "int")},
+									&schema.AddIndex{I: schema.NewIndex("idx_author")},
+									&schema.RenameIndex{From: schema.NewIndex("idx_a"), To: schema.NewIndex("title")},
+									&schema.AddForeignKey{F: schema.NewForeignKey("posts_author")},
+								},
+							},
+						},
+					},
+					{
+						Stmt:    &migrate.Stmt{Pos: 20, Text: "RENAME TABLE posts TO Posts"},
+						Changes: schema.Changes{&schema.RenameTable{From: posts, To: schema.NewTable("Posts")}},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	az, err := naming.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "naming",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.StringAttr("match", "^[a-z_]+$"),
+					schemahcl.StringAttr("message", "must be snake case"),
+				},
+				Children: []*schemahcl.Resource{
+					{
+						Type: "index",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringAttr("match", "^idx_"),
+						},
+					},
+					{
+						Type: "foreign_key",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringAttr("match", "^fk_"),
+							schemahcl.StringAttr("message", "must start with fk_"),
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, "naming violations detected", report.Text)
+	for i, d := range []struct {
+		code, text string
+		pos        int
+	}{
+		{"NM101", `Table named "Users" violates the naming policy: must be snake case`, 0},
+		{"NM102", `Column named "postID" on table "Users" violates the naming policy: must be snake case`, 0},
+		{"NM103", `Index named "users_id" on table "Users" violates the naming policy: must be snake case`, 0},
+		{"NM102", `Column named "Title" on table "posts" violates the naming policy: must be snake case`, 10},
+		{"NM103", `Index named "title" on table "posts" violates the naming policy: must be snake case`, 10},
+		{"NM104", `Foreign key named "posts_author" on table "posts" violates the naming policy: must start with fk_`, 10},
+		{"NM101", `Table named "Posts" violates the naming policy: must be snake case`, 20},
+	} {
+		require.Equal(t, d.code, report.Diagnostics[i].Code)
+		require.Equal(t, d.text, report.Diagnostics[i].Text)
+		require.Equal(t, d.pos, report.Diagnostics[i].Pos)
+	}
+	require.Len(t, report.Diagnostics, 7)
+
+	// Only objects with a configured pattern are checked.
+	az, err = naming.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "naming",
+				Children: []*schemahcl.Resource{
+					{
+						Type: "check",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringAttr("match", "^ck_"),
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, `Check constraint named "positive_id" on table "Users" violates the naming policy`, report.Diagnostics[0].Text)
+
+	_, err = naming.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "naming",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.StringAttr("match", "^[a-z"),
+				},
+			},
+		},
+	})
+	require.ErrorContains(t, err, "compiling naming match pattern")
+}
+
+type testFile struct {
+	name string
+	migrate.File
+}
+
+func (t testFile) Name() string {
+	return t.name
+}
@@ -44,6 +44,10 @@ type (
 		// Dev is a driver-specific environment used to execute analysis work.
 		Dev *sqlclient.Client
 
+		// Stats holds the table statistics of the target database, if provided.
+		// Analyzers may use it to report the impact of changes on large tables.
+		Stats *Stats
+
 		// Report reports analysis reports.
 		Reporter ReportWriter
 	}
@@ -93,6 +97,7 @@ type (
 		Text           string         `json:"Text"`                     // Diagnostic text.
 		Code           string         `json:"Code"`                     // Code describes the check. For example, DS101
 		SuggestedFixes []SuggestedFix `json:"SuggestedFixes,omitempty"` // Fixes to this specific diagnostics (statement-level).
+		Severity       string         `json:"Severity,omitempty"`       // Effective severity, set by the runner. For example, "warning".
 	}
 
 	// A SuggestedFix is a change associated with a diagnostic that can
@@ -127,6 +132,12 @@ type (
 	}
 )
 
+// Severity levels of diagnostics.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
 // SuggestFix appends a suggested fix to the diagnostic.
 func (d *Diagnostic) SuggestFix(m string, e *TextEdit) {
 	d.SuggestedFixes = append(d.SuggestedFixes, SuggestedFix{Message: m, TextEdit: e})
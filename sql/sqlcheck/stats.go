@@ -0,0 +1,134 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// TableStats holds the estimated size of a table in the target database.
+	TableStats struct {
+		Rows  int64 `json:"Rows"`            // Estimated number of rows.
+		Bytes int64 `json:"Bytes,omitempty"` // Estimated size on disk (in bytes), if known.
+	}
+
+	// Stats holds the table statistics of the target database, and the
+	// thresholds above which tables are considered large. For example:
+	//
+	//	lint {
+	//	  large_table {
+	//	    rows  = 1e6
+	//	    bytes = 10e9
+	//	  }
+	//	}
+	Stats struct {
+		// Tables holds the statistics of the tables, keyed by
+		// their qualified name. For example, "public.users".
+		Tables map[string]TableStats
+
+		// LargeRows and LargeBytes are the thresholds of large tables.
+		// A zero value disables the corresponding threshold.
+		LargeRows, LargeBytes int64
+	}
+
+	// StatsReader reads the table statistics of the database
+	// that is accessed by the given connection.
+	StatsReader func(context.Context, schema.ExecQuerier) (map[string]TableStats, error)
+)
+
+// Table returns the statistics of the given table. Tables are looked up by their qualified
+// name, and then by their name only, as the schema of the table in the dev database may be
+// named differently than in the target database.
+func (s *Stats) Table(t *schema.Table) (TableStats, bool) {
+	if s == nil || t == nil {
+		return TableStats{}, false
+	}
+	if t.Schema != nil {
+		if ts, ok := s.Tables[t.Schema.Name+"."+t.Name]; ok {
+			return ts, true
+		}
+	}
+	var (
+		found bool
+		ts    TableStats
+	)
+	for k, v := range s.Tables {
+		if strings.HasSuffix(k, "."+t.Name) {
+			// Ambiguous table name.
+			if found {
+				return TableStats{}, false
+			}
+			found, ts = true, v
+		}
+	}
+	return ts, found
+}
+
+// Large reports if the given table exceeds one of the thresholds.
+func (s *Stats) Large(t *schema.Table) bool {
+	ts, ok := s.Table(t)
+	return ok && (s.LargeRows > 0 && ts.Rows >= s.LargeRows || s.LargeBytes > 0 && ts.Bytes >= s.LargeBytes)
+}
+
+// Annotate appends the estimated size of the table to the diagnostic text, and
+// escalates the diagnostic to an error in case the table is large. Analyzers use
+// it for diagnostics whose impact depends on the size of the table.
+func (s *Stats) Annotate(d *Diagnostic, t *schema.Table) {
+	ts, ok := s.Table(t)
+	if !ok {
+		return
+	}
+	d.Text += fmt.Sprintf(" (%s)", ts)
+	if s.Large(t) {
+		d.Severity = SeverityError
+	}
+}
+
+// String returns the estimated size in a human-readable form.
+func (t TableStats) String() string {
+	s := fmt.Sprintf("~%d rows", t.Rows)
+	if t.Bytes > 0 {
+		s += ", " + formatBytes(t.Bytes)
+	}
+	return s
+}
+
+// formatBytes formats the given size using binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// driver specific stats readers.
+var statsReaders sync.Map
+
+// RegisterStats allows drivers to register a reader of table statistics.
+func RegisterStats(name string, f StatsReader) {
+	statsReaders.Store(name, f)
+}
+
+// StatsFor reads the table statistics using the reader that is registered for
+// the given driver name. An error is returned if no reader was registered.
+func StatsFor(ctx context.Context, name string, conn schema.ExecQuerier) (map[string]TableStats, error) {
+	f, ok := statsReaders.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("sql/sqlcheck: table statistics are not supported by driver %q", name)
+	}
+	return f.(StatsReader)(ctx, conn)
+}
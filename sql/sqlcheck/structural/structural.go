@@ -0,0 +1,383 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package structural
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// Analyzer checks the structure of tables that were added or changed in
+// the file: foreign keys without a supporting index, indexes that are covered
+// by other indexes. Tables without a primary key and nullable boolean columns
+// are reported only if enabled in the configuration:
+//
+//	lint {
+//	  structural {
+//	    primary_key   = true
+//	    nullable_bool = true
+//	  }
+//	}
+type Analyzer struct {
+	sqlcheck.Options
+	// PrimaryKey reports tables that were created without a primary key.
+	PrimaryKey bool
+	// NullableBool reports boolean columns that were added as nullable.
+	NullableBool bool
+}
+
+// New creates a new structural Analyzer with the given options.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing structural check options: %w", err)
+		}
+		for name, v := range map[string]*bool{"primary_key": &az.PrimaryKey, "nullable_bool": &az.NullableBool} {
+			a, ok := az.Remain().Attr(name)
+			if !ok {
+				continue
+			}
+			b, err := a.Bool()
+			if err != nil {
+				return nil, fmt.Errorf("sql/sqlcheck: parsing structural %s option: %w", name, err)
+			}
+			*v = b
+		}
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeFKNoIndex      = sqlcheck.Code("ST101")
+	codeNoPrimaryKey   = sqlcheck.Code("ST102")
+	codeRedundantIndex = sqlcheck.Code("ST103")
+	codeNullableBool   = sqlcheck.Code("ST104")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "structural"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	// The analysis is done on the state of the tables at the end of the file,
+	// as a foreign key or a primary key might be added by later statements.
+	if p.File.To == nil {
+		return nil
+	}
+	var (
+		diags []sqlcheck.Diagnostic
+		// Covered indexes that were already reported.
+		covered = make(map[*schema.Index]bool)
+	)
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			switch c := c.(type) {
+			case *schema.AddTable:
+				t, ok := tableOf(p.File.To, c.T)
+				if !ok {
+					continue
+				}
+				if a.PrimaryKey && t.PrimaryKey == nil {
+					diags = append(diags, sqlcheck.Diagnostic{
+						Code: codeNoPrimaryKey,
+						Pos:  sc.Stmt.Pos,
+						Text: fmt.Sprintf("Table %q was created without a primary key", t.Name),
+					})
+				}
+				for _, fk := range c.T.ForeignKeys {
+					if d, ok := fkIndex(p, sc.Stmt, t, fk); ok {
+						diags = append(diags, d)
+					}
+				}
+				for _, idx := range c.T.Indexes {
+					if d, ok := redundant(sc.Stmt, t, idx, covered); ok {
+						diags = append(diags, d)
+					}
+				}
+				for _, col := range c.T.Columns {
+					if d, ok := a.nullableBool(sc.Stmt, t, col); ok {
+						diags = append(diags, d)
+					}
+				}
+			case *schema.ModifyTable:
+				t, ok := tableOf(p.File.To, c.T)
+				if !ok {
+					continue
+				}
+				for _, mc := range c.Changes {
+					switch mc := mc.(type) {
+					case *schema.AddForeignKey:
+						if d, ok := fkIndex(p, sc.Stmt, t, mc.F); ok {
+							diags = append(diags, d)
+						}
+					case *schema.AddIndex:
+						if d, ok := redundant(sc.Stmt, t, mc.I, covered); ok {
+							diags = append(diags, d)
+						}
+					case *schema.AddColumn:
+						if d, ok := a.nullableBool(sc.Stmt, t, mc.C); ok {
+							diags = append(diags, d)
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "structural issues detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// fkIndex reports if the given foreign key exists in the final state of the
+// table, but its columns are not the leading columns of any index.
+func fkIndex(p *sqlcheck.Pass, stmt *migrate.Stmt, t *schema.Table, fk *schema.ForeignKey) (sqlcheck.Diagnostic, bool) {
+	fk, ok := t.ForeignKey(fk.Symbol)
+	if !ok || len(fk.Columns) == 0 {
+		return sqlcheck.Diagnostic{}, false
+	}
+	indexes := t.Indexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*schema.Index{t.PrimaryKey}, indexes...)
+	}
+	for _, idx := range indexes {
+		if leading(idx, fk.Columns) {
+			return sqlcheck.Diagnostic{}, false
+		}
+	}
+	names := make([]string, len(fk.Columns))
+	for i, c := range fk.Columns {
+		names[i] = c.Name
+	}
+	d := sqlcheck.Diagnostic{
+		Code: codeFKNoIndex,
+		Pos:  stmt.Pos,
+		Text: fmt.Sprintf("Foreign key %q on table %q has no index on its referencing columns", fk.Symbol, t.Name),
+	}
+	if create, ok := createIndex(p, t, identName(p, fmt.Sprintf("%s_%s", t.Name, strings.Join(names, "_"))), fk.Columns); ok {
+		d.SuggestFix(
+			fmt.Sprintf("Create an index on column(s) %s: %s", strings.Join(names, ", "), create),
+			appendStmt(p.File, stmt, create),
+		)
+	}
+	return d, true
+}
+
+// redundant reports if the given index is a duplicate of, or covered by, another
+// index, or if it makes another index redundant. Only the covered index is reported,
+// and indexes that were already reported are skipped. The primary key is never
+// reported, as it cannot be dropped in favor of another index.
+func redundant(stmt *migrate.Stmt, t *schema.Table, idx *schema.Index, reported map[*schema.Index]bool) (sqlcheck.Diagnostic, bool) {
+	idx, ok := t.Index(idx.Name)
+	if !ok {
+		return sqlcheck.Diagnostic{}, false
+	}
+	indexes := t.Indexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*schema.Index{t.PrimaryKey}, indexes...)
+	}
+	pos := slices.Index(indexes, idx)
+	for i, other := range indexes {
+		if other == idx {
+			continue
+		}
+		// Of two identical indexes, the latter is reported.
+		covered, by := idx, other
+		switch {
+		case covers(other, idx) && (!covers(idx, other) || i < pos):
+		case other != t.PrimaryKey && covers(idx, other) && (!covers(other, idx) || pos < i):
+			covered, by = other, idx
+		default:
+			continue
+		}
+		if reported[covered] {
+			continue
+		}
+		reported[covered] = true
+		text := fmt.Sprintf("Index %q on table %q is redundant, as its columns are a prefix of index %q", covered.Name, t.Name, indexName(t, by))
+		if len(covered.Parts) == len(by.Parts) {
+			text = fmt.Sprintf("Index %q on table %q is a duplicate of index %q", covered.Name, t.Name, indexName(t, by))
+		}
+		return sqlcheck.Diagnostic{
+			Code: codeRedundantIndex,
+			Pos:  stmt.Pos,
+			Text: text,
+		}, true
+	}
+	return sqlcheck.Diagnostic{}, false
+}
+
+// covers reports if the first index makes the second one redundant. A unique
+// index is covered only by a unique index with the same parts.
+func covers(idx, other *schema.Index) bool {
+	switch n, m := len(other.Parts), len(idx.Parts); {
+	case n == m:
+		return samePrefix(other, idx) && (!other.Unique || idx.Unique)
+	case n < m:
+		return samePrefix(other, idx) && !other.Unique
+	default:
+		return false
+	}
+}
+
+// nullableBool reports if the given column exists in the final state of the
+// table as a nullable boolean, as NULL is a third state of the column.
+func (a *Analyzer) nullableBool(stmt *migrate.Stmt, t *schema.Table, c *schema.Column) (sqlcheck.Diagnostic, bool) {
+	if !a.NullableBool {
+		return sqlcheck.Diagnostic{}, false
+	}
+	c, ok := t.Column(c.Name)
+	if !ok || c.Type == nil || !c.Type.Null {
+		return sqlcheck.Diagnostic{}, false
+	}
+	if _, ok := c.Type.Type.(*schema.BoolType); !ok {
+		return sqlcheck.Diagnostic{}, false
+	}
+	return sqlcheck.Diagnostic{
+		Code: codeNullableBool,
+		Pos:  stmt.Pos,
+		Text: fmt.Sprintf("Boolean column %q on table %q is nullable", c.Name, t.Name),
+	}, true
+}
+
+// leading reports if the given columns are the leading columns of the index.
+func leading(idx *schema.Index, columns []*schema.Column) bool {
+	if len(idx.Parts) < len(columns) {
+		return false
+	}
+	for _, c := range columns {
+		var found bool
+		for _, p := range idx.Parts[:len(columns)] {
+			if p.C != nil && p.C.Name == c.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// samePrefix reports if the parts of the first index are a prefix of the parts of the second.
+func samePrefix(idx, other *schema.Index) bool {
+	if len(idx.Parts) == 0 || len(idx.Parts) > len(other.Parts) {
+		return false
+	}
+	for i, p := range idx.Parts {
+		o := other.Parts[i]
+		if p.C == nil || o.C == nil || p.C.Name != o.C.Name || p.Desc != o.Desc {
+			return false
+		}
+	}
+	return true
+}
+
+// indexName returns the name of the index for reporting.
+func indexName(t *schema.Table, idx *schema.Index) string {
+	if idx == t.PrimaryKey && idx.Name == "" {
+		return "PRIMARY"
+	}
+	return idx.Name
+}
+
+// createIndex returns the CREATE INDEX statement for the given columns.
+func createIndex(p *sqlcheck.Pass, t *schema.Table, name string, columns []*schema.Column) (string, bool) {
+	if p.Dev == nil {
+		return "", false
+	}
+	sb, ok := p.Dev.Driver.(interface {
+		StmtBuilder(migrate.PlanOptions) *sqlx.Builder
+	})
+	if !ok {
+		return "", false
+	}
+	// Statements are added to migration files that are executed on
+	// the target schema, and therefore, should not be qualified.
+	b := sb.StmtBuilder(migrate.PlanOptions{SchemaQualifier: sqlx.P("")}).P("CREATE INDEX").Ident(name).P("ON").Table(t)
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(columns, func(i int, b *sqlx.Builder) {
+			b.Ident(columns[i].Name)
+		})
+	})
+	return b.String() + ";", true
+}
+
+// identName returns the given name, truncated to the maximum identifier length of the
+// dev database. Truncated names end with a hash of the full name to keep them unique.
+func identName(p *sqlcheck.Pass, name string) string {
+	var limit int
+	if p.Dev != nil {
+		switch p.Dev.Name {
+		case "mysql", "mariadb":
+			limit = 64
+		case "postgres":
+			limit = 63
+		}
+	}
+	if limit == 0 || len(name) <= limit {
+		return name
+	}
+	h := sha256.Sum256([]byte(name))
+	return fmt.Sprintf("%s_%x", name[:limit-9], h[:4])
+}
+
+// appendStmt returns the text edit that appends the given statement after the
+// statement. Text that shares the lines of the statement is kept as-is.
+func appendStmt(f *sqlcheck.File, stmt *migrate.Stmt, s string) *sqlcheck.TextEdit {
+	b := f.Bytes()
+	end := stmt.Pos + len(stmt.Text)
+	if stmt.Pos < 0 || end > len(b) || stmt.Text == "" || string(b[stmt.Pos:end]) != stmt.Text {
+		return nil
+	}
+	var (
+		start = bytes.LastIndexByte(b[:stmt.Pos], '\n') + 1
+		last  = len(b)
+	)
+	if i := bytes.IndexByte(b[end:], '\n'); i != -1 {
+		last = end + i
+	}
+	line := bytes.Count(b[:stmt.Pos], []byte("\n")) + 1
+	return &sqlcheck.TextEdit{
+		Line:    line,
+		End:     line + strings.Count(stmt.Text, "\n"),
+		NewText: strings.TrimRightFunc(string(b[start:last]), unicode.IsSpace) + "\n" + s,
+	}
+}
+
+// tableOf returns the state of the given table in the realm.
+func tableOf(r *schema.Realm, t *schema.Table) (*schema.Table, bool) {
+	if t.Schema != nil {
+		if s, ok := r.Schema(t.Schema.Name); ok {
+			return s.Table(t.Name)
+		}
+	}
+	// Schema-scoped realms might hold a single unnamed schema.
+	if len(r.Schemas) == 1 {
+		return r.Schemas[0].Table(t.Name)
+	}
+	return nil, false
+}
@@ -0,0 +1,207 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package structural_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/structural"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_Structure(t *testing.T) {
+	var (
+		s     = schema.New("main")
+		users = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("id", "int"))
+		posts = schema.NewTable("posts").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("author_id", "int"),
+				schema.NewStringColumn("title", "text"),
+			)
+		logs = schema.NewTable("logs").
+			AddColumns(schema.NewStringColumn("text", "text"))
+	)
+	s.AddTables(users, posts, logs)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	posts.SetPrimaryKey(schema.NewPrimaryKey(posts.Columns[0]))
+	posts.AddIndexes(
+		schema.NewIndex("posts_title").AddColumns(posts.Columns[2]),
+		schema.NewIndex("posts_title_author").AddColumns(posts.Columns[2], posts.Columns[1]),
+		schema.NewIndex("posts_id").AddColumns(posts.Columns[0]),
+	)
+	posts.AddForeignKeys(
+		schema.NewForeignKey("author").AddColumns(posts.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+	)
+	const text = "CREATE TABLE `users` (`id` int, PRIMARY KEY (`id`));\n" +
+		"CREATE TABLE `logs` (`text` text);\n" +
+		"CREATE TABLE `posts` (\n  `id` int,\n  `author_id` int,\n  `title` text,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `author` FOREIGN KEY (`author_id`) REFERENCES `users` (`id`)\n);\n" +
+		"CREATE INDEX `posts_title` ON `posts` (`title`);\n" +
+		"CREATE INDEX `posts_title_author` ON `posts` (`title`, `author_id`);\n" +
+		"CREATE INDEX `posts_id` ON `posts` (`id`);\n"
+	var report *sqlcheck.Report
+	stmts, err := (&sqlite.Driver{}).ScanStmts(text)
+	require.NoError(t, err)
+	require.Len(t, stmts, 6)
+	pass := &sqlcheck.Pass{
+		Dev: &sqlclient.Client{
+			Driver: &sqlite.Driver{},
+		},
+		File: &sqlcheck.File{
+			File: migrate.NewLocalFile("1.sql", []byte(text)),
+			To:   schema.NewRealm(s),
+			Changes: []*sqlcheck.Change{
+				{Stmt: stmts[0], Changes: schema.Changes{&schema.AddTable{T: users}}},
+				{Stmt: stmts[1], Changes: schema.Changes{&schema.AddTable{T: logs}}},
+				{Stmt: stmts[2], Changes: schema.Changes{&schema.AddTable{T: schema.NewTable("posts").AddForeignKeys(posts.ForeignKeys...)}}},
+				{Stmt: stmts[3], Changes: schema.Changes{&schema.ModifyTable{T: posts, Changes: schema.Changes{&schema.AddIndex{I: posts.Indexes[0]}}}}},
+				{Stmt: stmts[4], Changes: schema.Changes{&schema.ModifyTable{T: posts, Changes: schema.Changes{&schema.AddIndex{I: posts.Indexes[1]}}}}},
+				{Stmt: stmts[5], Changes: schema.Changes{&schema.ModifyTable{T: posts, Changes: schema.Changes{&schema.AddIndex{I: posts.Indexes[2]}}}}},
+			},
+		},
+		Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+			report = &r
+		}),
+	}
+	az, err := structural.New(nil)
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, "structural issues detected", report.Text)
+	require.Len(t, report.Diagnostics, 3)
+
+	d := report.Diagnostics[0]
+	require.Equal(t, "ST101", d.Code)
+	require.Equal(t, stmts[2].Pos, d.Pos)
+	require.Equal(t, `Foreign key "author" on table "posts" has no index on its referencing columns`, d.Text)
+	require.Len(t, d.SuggestedFixes, 1)
+	require.Equal(t, "Create an index on column(s) author_id: CREATE INDEX `posts_author_id` ON `posts` (`author_id`);", d.SuggestedFixes[0].Message)
+	require.Equal(t, &sqlcheck.TextEdit{
+		Line:    3,
+		End:     9,
+		NewText: stmts[2].Text + "\nCREATE INDEX `posts_author_id` ON `posts` (`author_id`);",
+	}, d.SuggestedFixes[0].TextEdit)
+
+	// Each covered index is reported once.
+	require.Equal(t, "ST103", report.Diagnostics[1].Code)
+	require.Equal(t, stmts[3].Pos, report.Diagnostics[1].Pos)
+	require.Equal(t, `Index "posts_title" on table "posts" is redundant, as its columns are a prefix of index "posts_title_author"`, report.Diagnostics[1].Text)
+	require.Equal(t, stmts[5].Pos, report.Diagnostics[2].Pos)
+	require.Equal(t, `Index "posts_id" on table "posts" is a duplicate of index "PRIMARY"`, report.Diagnostics[2].Text)
+
+	// Tables without a primary key are reported only if enabled.
+	az, err = structural.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "structural",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("primary_key", true),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, az.PrimaryKey)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 4)
+	require.Equal(t, "ST102", report.Diagnostics[0].Code)
+	require.Equal(t, stmts[1].Pos, report.Diagnostics[0].Pos)
+	require.Equal(t, `Table "logs" was created without a primary key`, report.Diagnostics[0].Text)
+
+	// Indexes that start with the primary key columns do not make it redundant,
+	// and a pre-existing index is reported when a covering index is added.
+	changes := pass.File.Changes
+	idx1 := schema.NewIndex("posts_id_title").AddColumns(posts.Columns[0], posts.Columns[2])
+	posts.AddIndexes(idx1)
+	pass.File.Changes = []*sqlcheck.Change{
+		{Stmt: stmts[4], Changes: schema.Changes{&schema.ModifyTable{T: posts, Changes: schema.Changes{&schema.AddIndex{I: idx1}}}}},
+	}
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, `Index "posts_id" on table "posts" is redundant, as its columns are a prefix of index "posts_id_title"`, report.Diagnostics[0].Text)
+	posts.Indexes = posts.Indexes[:3]
+	pass.File.Changes = changes
+
+	// A supporting index that is added later in the file.
+	posts.AddIndexes(schema.NewIndex("posts_author").AddColumns(posts.Columns[1]))
+	report = nil
+	pass.File.Changes = pass.File.Changes[2:3]
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Nil(t, report)
+
+	// Nullable boolean columns are reported only if enabled.
+	users.AddColumns(schema.NewNullBoolColumn("active", "bool"), schema.NewBoolColumn("admin", "bool"))
+	pass.File.Changes = []*sqlcheck.Change{
+		{Stmt: stmts[0], Changes: schema.Changes{&schema.ModifyTable{T: users, Changes: schema.Changes{&schema.AddColumn{C: users.Columns[1]}, &schema.AddColumn{C: users.Columns[2]}}}}},
+	}
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Nil(t, report)
+	az, err = structural.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "structural",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("nullable_bool", true),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, az.NullableBool)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "ST104", report.Diagnostics[0].Code)
+	require.Equal(t, `Boolean column "active" on table "users" is nullable`, report.Diagnostics[0].Text)
+}
+
+func TestAnalyzer_FKIndexName(t *testing.T) {
+	var (
+		s      = schema.New("main")
+		parent = schema.NewTable("parent").AddColumns(schema.NewIntColumn("id", "int"))
+		child  = schema.NewTable("child_table_with_a_name_long_enough_to_overflow").
+			AddColumns(schema.NewIntColumn("parent_identifier_column", "int"))
+	)
+	s.AddTables(parent, child)
+	parent.SetPrimaryKey(schema.NewPrimaryKey(parent.Columns[0]))
+	child.AddForeignKeys(
+		schema.NewForeignKey("parent").AddColumns(child.Columns[0]).SetRefTable(parent).AddRefColumns(parent.Columns[0]),
+	)
+	const text = "-- create child table\nCREATE TABLE `child` (`parent_id` int);\n"
+	var report *sqlcheck.Report
+	pass := &sqlcheck.Pass{
+		Dev: &sqlclient.Client{
+			Name:   "mysql",
+			Driver: &sqlite.Driver{},
+		},
+		File: &sqlcheck.File{
+			File: migrate.NewLocalFile("1.sql", []byte(text)),
+			To:   schema.NewRealm(s),
+			Changes: []*sqlcheck.Change{
+				// The statement text does not match the file content at its position.
+				{Stmt: &migrate.Stmt{Pos: 0, Text: "CREATE TABLE `child` (`parent_id` int);"}, Changes: schema.Changes{&schema.AddTable{T: child}}},
+			},
+		},
+		Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+			report = &r
+		}),
+	}
+	az, err := structural.New(nil)
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	d := report.Diagnostics[0]
+	require.Len(t, d.SuggestedFixes, 1)
+	// Index names are truncated to the identifier limit of the database.
+	require.Equal(t, "Create an index on column(s) parent_identifier_column: CREATE INDEX `child_table_with_a_name_long_enough_to_overflow_parent__6c44c0dc` ON `child_table_with_a_name_long_enough_to_overflow` (`parent_identifier_column`);", d.SuggestedFixes[0].Message)
+	require.Nil(t, d.SuggestedFixes[0].TextEdit, "statements that do not match the file are not edited")
+}
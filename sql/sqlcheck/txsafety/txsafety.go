@@ -0,0 +1,152 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package txsafety
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// Analyzer checks that the statements in a migration file can be executed
+	// safely in the transaction mode the file runs in. Files are executed in a
+	// transaction by default, unless the "atlas:txmode none" directive is set.
+	Analyzer struct {
+		sqlcheck.Options
+		Handler
+	}
+
+	// Handler holds the underlying driver handlers.
+	Handler struct {
+		// NoTx returns a short description of the given statement, in case it
+		// cannot be executed inside a transaction block. For example, "VACUUM".
+		NoTx func(*sqlcheck.Pass, *migrate.Stmt) (string, bool)
+
+		// ImplicitCommit indicates the database commits the active transaction
+		// implicitly when executing DDL statements. For example, MySQL.
+		ImplicitCommit bool
+	}
+)
+
+// New creates a new transaction-safety Analyzer with the given options.
+func New(r *schemahcl.Resource, h Handler) (*Analyzer, error) {
+	az := &Analyzer{Handler: h}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing tx_safety check options: %w", err)
+		}
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeNoTxStmt = sqlcheck.Code("TX101")
+	codeMixedDDL = sqlcheck.Code("TX102")
+)
+
+var (
+	reDMLStmt     = regexp.MustCompile(`(?i)^\s*(INSERT|UPDATE|DELETE|REPLACE)\s`)
+	reTxDirective = regexp.MustCompile(`^(--|#)\s*atlas:txmode(\s|$)`)
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "tx_safety"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	var (
+		noTx     bool
+		diags    []sqlcheck.Diagnostic
+		ddl, dml *migrate.Stmt
+	)
+	// Files that are executed without a transaction can contain non-transactional
+	// statements. Mixed files are still reported, as implicit commits take place
+	// regardless of the transaction mode.
+	if d, ok := p.File.File.(interface{ Directive(string) []string }); ok {
+		ds := d.Directive("txmode")
+		noTx = len(ds) > 0 && ds[0] == "none"
+	}
+	for _, sc := range p.File.Changes {
+		// Statements can be empty in case the file was analyzed as a whole.
+		if sc.Stmt == nil || sc.Stmt.Text == "" {
+			continue
+		}
+		if a.NoTx != nil && !noTx {
+			if what, ok := a.NoTx(p, sc.Stmt); ok {
+				d := sqlcheck.Diagnostic{
+					Code: codeNoTxStmt,
+					Pos:  sc.Stmt.Pos,
+					Text: fmt.Sprintf("%s cannot be executed inside a transaction block, but the file is executed in a transaction", what),
+				}
+				d.SuggestFix("Add the atlas:txmode none directive to execute the file without a transaction", txmodeNone(p.File))
+				diags = append(diags, d)
+			}
+		}
+		if !a.ImplicitCommit {
+			continue
+		}
+		var mixed bool
+		switch prev := ddl != nil && dml != nil; {
+		case reDMLStmt.MatchString(sc.Stmt.Text):
+			dml, mixed = sc.Stmt, !prev && ddl != nil
+		case len(sc.Changes) > 0:
+			ddl, mixed = sc.Stmt, !prev && dml != nil
+		}
+		if mixed {
+			d := sqlcheck.Diagnostic{
+				Code: codeMixedDDL,
+				Pos:  sc.Stmt.Pos,
+				Text: "Schema changes (DDL) are mixed with data changes (DML) in the file. DDL statements commit the transaction implicitly, and a failure might leave the file partially applied",
+			}
+			// Changing the transaction mode does not help here, as DDL statements commit
+			// implicitly in all modes. Keeping schema changes and data changes in separate
+			// files ensures data changes are executed atomically, and can be re-executed.
+			d.SuggestFix("Move the data changes to a separate migration file, to execute them in their own transaction", nil)
+			diags = append(diags, d)
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "transaction safety issues detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// txmodeNone returns the text edit that sets the txmode directive of the file to none.
+func txmodeNone(f *sqlcheck.File) *sqlcheck.TextEdit {
+	const directive = "-- atlas:txmode none"
+	var (
+		i     int
+		lines = strings.Split(string(f.Bytes()), "\n")
+	)
+	for i < len(lines) && (strings.HasPrefix(lines[i], "--") || strings.HasPrefix(lines[i], "#")) {
+		i++
+	}
+	// File directives are located in the header comments of the file, and
+	// are separated by double new lines from the first statement.
+	if i == 0 || i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		return &sqlcheck.TextEdit{Line: 1, End: 1, NewText: directive + "\n\n" + lines[0]}
+	}
+	for j, l := range lines[:i] {
+		if reTxDirective.MatchString(l) {
+			return &sqlcheck.TextEdit{Line: j + 1, End: j + 1, NewText: directive}
+		}
+	}
+	return &sqlcheck.TextEdit{Line: 1, End: 1, NewText: directive + "\n" + lines[0]}
+}
@@ -0,0 +1,144 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package txsafety_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/txsafety"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_NoTx(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		pass   = func(text string) *sqlcheck.Pass {
+			f := migrate.NewLocalFile("1.sql", []byte(text))
+			stmts, err := f.StmtDecls()
+			require.NoError(t, err)
+			changes := make([]*sqlcheck.Change, len(stmts))
+			for i, s := range stmts {
+				changes[i] = &sqlcheck.Change{Stmt: s}
+			}
+			return &sqlcheck.Pass{
+				Dev:  &sqlclient.Client{},
+				File: &sqlcheck.File{File: f, Changes: changes},
+				Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+					report = &r
+				}),
+			}
+		}
+	)
+	az, err := txsafety.New(nil, txsafety.Handler{
+		NoTx: func(_ *sqlcheck.Pass, s *migrate.Stmt) (string, bool) {
+			return "VACUUM", strings.HasPrefix(s.Text, "VACUUM")
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass("CREATE TABLE t(c int);\nVACUUM;\n")))
+	require.Equal(t, "transaction safety issues detected", report.Text)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "TX101", report.Diagnostics[0].Code)
+	require.Equal(t, 23, report.Diagnostics[0].Pos)
+	require.Equal(t, "VACUUM cannot be executed inside a transaction block, but the file is executed in a transaction", report.Diagnostics[0].Text)
+	require.Equal(t, []sqlcheck.SuggestedFix{
+		{
+			Message:  "Add the atlas:txmode none directive to execute the file without a transaction",
+			TextEdit: &sqlcheck.TextEdit{Line: 1, End: 1, NewText: "-- atlas:txmode none\n\nCREATE TABLE t(c int);"},
+		},
+	}, report.Diagnostics[0].SuggestedFixes)
+
+	// Existing directives are replaced.
+	report = nil
+	require.NoError(t, az.Analyze(context.Background(), pass("-- atlas:sum ignore\n-- atlas:txmode file\n\nVACUUM;\n")))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, &sqlcheck.TextEdit{Line: 2, End: 2, NewText: "-- atlas:txmode none"}, report.Diagnostics[0].SuggestedFixes[0].TextEdit)
+
+	// Comments that are attached to statements are not file directives.
+	report = nil
+	require.NoError(t, az.Analyze(context.Background(), pass("-- atlas:txmode file\nVACUUM;\n")))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, &sqlcheck.TextEdit{Line: 1, End: 1, NewText: "-- atlas:txmode none\n\n-- atlas:txmode file"}, report.Diagnostics[0].SuggestedFixes[0].TextEdit)
+
+	// Files that are executed without a transaction are skipped.
+	report = nil
+	require.NoError(t, az.Analyze(context.Background(), pass("-- atlas:txmode none\n\nVACUUM;\n")))
+	require.Nil(t, report)
+}
+
+func TestAnalyzer_ImplicitCommit(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users")
+		text   = "CREATE TABLE users(id int);\nINSERT INTO users VALUES (1);\nALTER TABLE users ADD COLUMN c int;\nUPDATE users SET c = 1;\n"
+		f      = migrate.NewLocalFile("1.sql", []byte(text))
+	)
+	stmts, err := f.StmtDecls()
+	require.NoError(t, err)
+	require.Len(t, stmts, 4)
+	pass := &sqlcheck.Pass{
+		Dev: &sqlclient.Client{},
+		File: &sqlcheck.File{
+			File: f,
+			Changes: []*sqlcheck.Change{
+				{Stmt: stmts[0], Changes: schema.Changes{&schema.AddTable{T: users}}},
+				{Stmt: stmts[1]},
+				{Stmt: stmts[2], Changes: schema.Changes{&schema.ModifyTable{T: users}}},
+				{Stmt: stmts[3]},
+			},
+		},
+		Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+			report = &r
+		}),
+	}
+	az, err := txsafety.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "tx_safety",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("error", true),
+				},
+			},
+		},
+	}, txsafety.Handler{ImplicitCommit: true})
+	require.NoError(t, err)
+	require.EqualError(t, az.Analyze(context.Background(), pass), "transaction safety issues detected")
+	// Mixed statements are reported once.
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "TX102", report.Diagnostics[0].Code)
+	require.Equal(t, stmts[1].Pos, report.Diagnostics[0].Pos)
+	require.Equal(t, "Schema changes (DDL) are mixed with data changes (DML) in the file. DDL statements commit the transaction implicitly, and a failure might leave the file partially applied", report.Diagnostics[0].Text)
+	require.Equal(t, []sqlcheck.SuggestedFix{
+		{Message: "Move the data changes to a separate migration file, to execute them in their own transaction"},
+	}, report.Diagnostics[0].SuggestedFixes)
+
+	// Mixed files are reported regardless of the transaction mode.
+	report = nil
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:txmode none\n\n"+text))
+	stmts, err = f.StmtDecls()
+	require.NoError(t, err)
+	pass.File.File = f
+	for i := range pass.File.Changes {
+		pass.File.Changes[i].Stmt = stmts[i]
+	}
+	require.Error(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "TX102", report.Diagnostics[0].Code)
+
+	// Drivers with transactional DDL are not affected.
+	report = nil
+	az, err = txsafety.New(nil, txsafety.Handler{})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Nil(t, report)
+}
@@ -222,6 +222,36 @@ func (*Driver) ScanStmts(input string) ([]*migrate.Stmt, error) {
 	}).Scan(input)
 }
 
+// SetStmtTimeout implements migrate.StmtTimeoutSetter. SQLite does not limit the execution
+// time of statements, and the timeout is used only for waiting on locks (busy_timeout).
+func (d *Driver) SetStmtTimeout(ctx context.Context, timeout time.Duration) (migrate.RestoreFunc, error) {
+	if !sqlx.IsSingleConn(d.ExecQuerier) {
+		return func(context.Context) error { return nil }, nil
+	}
+	rows, err := d.QueryContext(ctx, "PRAGMA busy_timeout")
+	if err != nil {
+		return nil, err
+	}
+	var prev int64
+	if err := sqlx.ScanOne(rows, &prev); err != nil {
+		return nil, err
+	}
+	if _, err := d.ExecContext(ctx, fmt.Sprintf("PRAGMA busy_timeout = %d", max(timeout.Milliseconds(), 1))); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		_, err := d.ExecContext(ctx, fmt.Sprintf("PRAGMA busy_timeout = %d", prev))
+		return err
+	}, nil
+}
+
+// IsRetryable reports if the given statement execution error is transient, and the statement
+// can be retried, which is the case when the database is locked (SQLITE_BUSY). It is intended
+// to be used with migrate.WithRetryPolicy.
+func IsRetryable(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "database is locked") || strings.Contains(err.Error(), "SQLITE_BUSY"))
+}
+
 func acquireLock(path string, timeout time.Duration) (schema.UnlockFunc, error) {
 	lock, err := os.Create(path)
 	if err != nil {
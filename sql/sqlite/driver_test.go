@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -65,6 +66,33 @@ func TestDriver_LockAcquired(t *testing.T) {
 	_, err = drv.Lock(context.Background(), "another", time.Second)
 }
 
+func TestDriver_SetStmtTimeout(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	m.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	m.ExpectQuery("PRAGMA busy_timeout").
+		WillReturnRows(sqlmock.NewRows([]string{"timeout"}).AddRow(5000))
+	m.ExpectExec("PRAGMA busy_timeout = 1500").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec("PRAGMA busy_timeout = 5000").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	d := &Driver{conn: &conn{ExecQuerier: tx}}
+	restore, err := d.SetStmtTimeout(ctx, 1500*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, restore(ctx))
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.True(t, IsRetryable(errors.New("database is locked (5) (SQLITE_BUSY)")))
+	require.True(t, IsRetryable(errors.New("database is locked")))
+	require.False(t, IsRetryable(errors.New("no such table: t")))
+	require.False(t, IsRetryable(nil))
+}
+
 func TestDriver_CheckClean(t *testing.T) {
 	var (
 		r   = schema.NewRealm()
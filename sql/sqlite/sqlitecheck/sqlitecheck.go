@@ -6,22 +6,36 @@ package sqlitecheck
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/brandedcheck"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
+	"ariga.io/atlas/sql/sqlcheck/custom"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
+	"ariga.io/atlas/sql/sqlcheck/dataloss"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
+	"ariga.io/atlas/sql/sqlcheck/dmlsafety"
 	"ariga.io/atlas/sql/sqlcheck/incompatible"
+	"ariga.io/atlas/sql/sqlcheck/naming"
+	"ariga.io/atlas/sql/sqlcheck/structural"
+	"ariga.io/atlas/sql/sqlcheck/txsafety"
 	"ariga.io/atlas/sql/sqlite"
 )
 
-// codeModNotNullC is an SQLite specific code for reporting modifying nullable columns to non-nullable.
-var codeModNotNullC = sqlcheck.Code("LT101")
+var (
+	// codeModNotNullC is an SQLite specific code for reporting modifying nullable columns to non-nullable.
+	codeModNotNullC = sqlcheck.Code("LT101")
+	// codeModAffinity is an SQLite specific code for reporting changing text columns to numeric affinity.
+	codeModAffinity = sqlcheck.Code("LT102")
+)
 
 func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err error) {
 	tt, err := sqlite.FormatType(p.Column.Type.Type)
@@ -52,6 +66,100 @@ func modifyNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err e
 	}, nil
 }
 
+// modifyType reports column type changes from TEXT to NUMERIC, INTEGER or REAL
+// affinity. SQLite does not enforce the length or precision of types, but values
+// copied to the new column are converted to numbers if they look like numbers.
+// For example, '007' is stored as 7. See: https://www.sqlite.org/datatype3.html.
+func modifyType(p *dataloss.ColumnPass) ([]sqlcheck.Diagnostic, error) {
+	if _, ok := p.Modify.From.Type.Type.(*schema.StringType); !ok {
+		return nil, nil
+	}
+	switch p.Modify.To.Type.Type.(type) {
+	case *schema.IntegerType, *schema.DecimalType, *schema.FloatType, *schema.BoolType:
+	default:
+		return nil, nil
+	}
+	from, err := sqlite.FormatType(p.Modify.From.Type.Type)
+	if err != nil {
+		return nil, err
+	}
+	to, err := sqlite.FormatType(p.Modify.To.Type.Type)
+	if err != nil {
+		return nil, err
+	}
+	return []sqlcheck.Diagnostic{
+		{
+			Pos:  p.Change.Stmt.Pos,
+			Code: codeModAffinity,
+			Text: fmt.Sprintf("Changing column %q on table %q from %q to %q converts existing text values that look like numbers", p.Modify.To.Name, p.Table.Name, from, to),
+		},
+	}, nil
+}
+
+// reVacuum matches the VACUUM statement that cannot be executed inside a transaction.
+var reVacuum = regexp.MustCompile(`(?i)^\s*VACUUM\b`)
+
+// noTx reports if the statement cannot be executed inside a transaction block.
+func noTx(_ *sqlcheck.Pass, s *migrate.Stmt) (string, bool) {
+	return "VACUUM", reVacuum.MatchString(s.Text)
+}
+
+// tableStats reads the estimated number of rows of the tables in the main database from the
+// sqlite_stat1 table, as collected by the last ANALYZE command. Counting the rows instead
+// requires a full scan of each table. Tables that were never analyzed are skipped. The size
+// of the tables is read from the dbstat virtual table, in case SQLite was compiled with it
+// (SQLITE_ENABLE_DBSTAT_VTAB).
+func tableStats(ctx context.Context, conn schema.ExecQuerier) (map[string]sqlcheck.TableStats, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT COUNT(*) FROM `sqlite_master` WHERE `type` = 'table' AND `name` = 'sqlite_stat1'")
+	if err != nil {
+		return nil, err
+	}
+	var analyzed bool
+	if err := sqlx.ScanOne(rows, &analyzed); err != nil || !analyzed {
+		return nil, err
+	}
+	// The first number of the stat column holds the number of rows in the table.
+	rows, err = conn.QueryContext(ctx, "SELECT `tbl`, MAX(CAST(`stat` AS INTEGER)) FROM `sqlite_stat1` WHERE `tbl` NOT LIKE 'sqlite_%' GROUP BY `tbl`")
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]sqlcheck.TableStats)
+	for rows.Next() {
+		var (
+			t string
+			n int64
+		)
+		if err := rows.Scan(&t, &n); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats["main."+t] = sqlcheck.TableStats{Rows: n}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, err
+	}
+	if rows, err := conn.QueryContext(ctx, "SELECT `name`, SUM(`pgsize`) FROM `dbstat` GROUP BY `name`"); err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				t    string
+				size int64
+			)
+			if err := rows.Scan(&t, &size); err != nil {
+				return nil, err
+			}
+			if ts, ok := stats["main."+t]; ok {
+				ts.Bytes = size
+				stats["main."+t] = ts
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
 func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	ds, err := destructive.New(r)
 	if err != nil {
@@ -72,6 +180,40 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
+	// SQLite does not enforce the capacity of types, and
+	// only type affinity changes are reported.
+	dl, err := dataloss.New(r, dataloss.Handler{
+		ModifyType: modifyType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	nm, err := naming.New(r)
+	if err != nil {
+		return nil, err
+	}
+	st, err := structural.New(r)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txsafety.New(r, txsafety.Handler{
+		NoTx: noTx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dm, err := dmlsafety.New(r, dmlsafety.Dialect{})
+	if err != nil {
+		return nil, err
+	}
+	br, err := brandedcheck.New(r)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := custom.New(r)
+	if err != nil {
+		return nil, err
+	}
 	return []sqlcheck.Analyzer{
 		sqlcheck.AnalyzerFunc(func(_ context.Context, p *sqlcheck.Pass) error {
 			var changes []*sqlcheck.Change
@@ -114,7 +256,7 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 			p.File.Changes = changes
 			return nil
 		}),
-		ds, dd, cd, bc,
+		ds, dd, cd, bc, dl, nm, st, tx, dm, br, cr,
 	}, nil
 }
 
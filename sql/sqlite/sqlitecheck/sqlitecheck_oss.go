@@ -13,4 +13,5 @@ import (
 
 func init() {
 	sqlcheck.Register(sqlite.DriverName, analyzers)
+	sqlcheck.RegisterStats(sqlite.DriverName, tableStats)
 }
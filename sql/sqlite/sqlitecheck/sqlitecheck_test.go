@@ -141,6 +141,56 @@ func TestDetectModifyTable(t *testing.T) {
 	require.Equal(t, report.Diagnostics[0].Text, `Modifying nullable column "text" to non-nullable without default value might fail in case it contains NULL values`)
 }
 
+func TestModifyAffinity(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		pass   = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{
+				Driver: &sqlite.Driver{},
+			},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Text: "ALTER TABLE `users` ...",
+						},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: schema.NewTable("users").
+									SetSchema(schema.New("main")),
+								Changes: schema.Changes{
+									// Length of types is not enforced.
+									&schema.ModifyColumn{
+										From:   schema.NewStringColumn("name", "varchar", schema.StringSize(255)),
+										To:     schema.NewStringColumn("name", "varchar", schema.StringSize(64)),
+										Change: schema.ChangeType,
+									},
+									&schema.ModifyColumn{
+										From:   schema.NewStringColumn("zip", "text"),
+										To:     schema.NewIntColumn("zip", "integer"),
+										Change: schema.ChangeType,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	azs, err := sqlcheck.AnalyzerFor(sqlite.DriverName, nil)
+	require.NoError(t, err)
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.Equal(t, "data loss changes detected", report.Text)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "LT102", report.Diagnostics[0].Code)
+	require.Equal(t, `Changing column "zip" on table "users" from "text" to "integer" converts existing text values that look like numbers`, report.Diagnostics[0].Text)
+}
+
 type testFile struct {
 	name string
 	migrate.File